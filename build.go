@@ -0,0 +1,167 @@
+package intset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FromInts returns a new set holding the integers in ns. The
+// integers are sorted, deduplicated and coalesced into ranges in one
+// pass, which is considerably faster than AddInts for large inputs.
+// The slice ns is not modified.
+func FromInts(ns []int) *IntSet {
+	s := make([]int, len(ns))
+	copy(s, ns)
+	sort.Ints(s)
+
+	return fromSorted(s)
+}
+
+// FromSortedInts returns a new set holding the integers in ns, which
+// must be sorted in ascending order. Duplicates are allowed. If ns
+// turns out not to be sorted, FromSortedInts falls back to FromInts.
+func FromSortedInts(ns []int) *IntSet {
+	if !sort.IntsAreSorted(ns) {
+		return FromInts(ns)
+	}
+
+	return fromSorted(ns)
+}
+
+// fromSorted coalesces a sorted slice of integers into a set.
+func fromSorted(ns []int) *IntSet {
+	n := &IntSet{}
+	var cur *Element
+	for _, i := range ns {
+		if cur != nil && (cur.last == intMax || i <= cur.last+1) {
+			if i > cur.last {
+				cur.last = i
+			}
+			continue
+		}
+		cur = &Element{first: i, last: i}
+		n.elements = append(n.elements, cur)
+	}
+
+	return n
+}
+
+// Builder collects integers and ranges in any order and builds a set
+// from them in a single sort and coalesce step. The zero value is
+// ready to use.
+type Builder struct {
+	ranges [][2]int
+}
+
+// Add adds the integer n to the builder.
+func (b *Builder) Add(n int) {
+	b.ranges = append(b.ranges, [2]int{n, n})
+}
+
+// AddRange adds the range from x to y to the builder. The bounds may
+// be given in any order.
+func (b *Builder) AddRange(x, y int) {
+	if y < x {
+		x, y = y, x
+	}
+	b.ranges = append(b.ranges, [2]int{x, y})
+}
+
+// Build returns a set holding everything added to the builder, and
+// resets the builder.
+func (b *Builder) Build() *IntSet {
+	ranges := b.ranges
+	b.ranges = nil
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+
+	n := &IntSet{}
+	var cur *Element
+	for _, r := range ranges {
+		if cur != nil && (cur.last == intMax || r[0] <= cur.last+1) {
+			if r[1] > cur.last {
+				cur.last = r[1]
+			}
+			continue
+		}
+		cur = &Element{first: r[0], last: r[1]}
+		n.elements = append(n.elements, cur)
+	}
+
+	return n
+}
+
+// ReadFrom reads newline or comma separated integers from r and adds
+// them to the set, in compliance with the io.ReaderFrom interface.
+// Blank lines and surrounding white space are ignored. The input is
+// parsed while streaming, and the set is only updated if the whole
+// input was parsed successfully.
+func (a *IntSet) ReadFrom(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+	b := &Builder{}
+
+	var read int64
+	var tok strings.Builder
+	line := 1
+
+	flush := func() error {
+		s := strings.TrimSpace(tok.String())
+		tok.Reset()
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("intset: line %d: invalid integer %q", line, s)
+		}
+		b.Add(n)
+		return nil
+	}
+
+	for {
+		c, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return read, err
+		}
+		read++
+
+		switch c {
+		case ',', '\n':
+			if err := flush(); err != nil {
+				return read, err
+			}
+			if c == '\n' {
+				line++
+			}
+		default:
+			tok.WriteByte(c)
+		}
+	}
+	if err := flush(); err != nil {
+		return read, err
+	}
+
+	n := b.Build()
+	var added *IntSet
+	if len(a.observers) > 0 {
+		added = n.Difference(a)
+	}
+	if len(a.elements) == 0 {
+		a.elements = n.elements
+	} else {
+		a.elements = a.Union(n).elements
+	}
+	if added != nil {
+		a.notify(added, New())
+	}
+
+	return read, nil
+}
@@ -0,0 +1,88 @@
+package intset
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFromInts(t *testing.T) {
+	a := FromInts([]int{9, 3, 1, 2, 2, 7, 8, -4, 10})
+	e := "{-4, 1:3, 7:10}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from ints failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestFromIntsMaxInt(t *testing.T) {
+	a := FromInts([]int{intMax, intMax - 1, intMin})
+	e := fmt.Sprintf("{%d, %d:%d}", intMin, intMax-1, intMax)
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from ints failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestFromSortedInts(t *testing.T) {
+	a := FromSortedInts([]int{1, 1, 2, 3, 5, 6, 9})
+	e := "{1:3, 5:6, 9}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from sorted ints failed: got %s, expected %s", a, e)
+	}
+
+	a = FromSortedInts([]int{5, 1, 2})
+	e = "{1:2, 5}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from sorted ints failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	b := &Builder{}
+	b.AddRange(20, 10)
+	b.Add(5)
+	b.Add(21)
+	b.AddRange(-3, 4)
+	b.AddRange(30, 40)
+	b.Add(35)
+
+	a := b.Build()
+	e := "{-3:5, 10:21, 30:40}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("builder failed: got %s, expected %s", a, e)
+	}
+
+	if a = b.Build(); len(a.elements) != 0 {
+		t.Fatalf("builder failed: got %s after reset, expected {∅}", a)
+	}
+}
+
+func TestReadFrom(t *testing.T) {
+	a := New(Range(100, 200))
+	in := "1,2, 3\n\n 7\r\n8,9,\n-1\n"
+	n, err := a.ReadFrom(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("read from failed: %v", err)
+	}
+	if n != int64(len(in)) {
+		t.Fatalf("read from failed: read %d bytes, expected %d", n, len(in))
+	}
+	e := "{-1, 1:3, 7:9, 100:200}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("read from failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestReadFromInvalid(t *testing.T) {
+	a := New(Int(1))
+	_, err := a.ReadFrom(strings.NewReader("1\n2\nthree\n"))
+	if err == nil {
+		t.Fatalf("read from failed: expected error for invalid input")
+	}
+	e := `intset: line 3: invalid integer "three"`
+	if err.Error() != e {
+		t.Fatalf("read from failed: got error %q, expected %q", err, e)
+	}
+	if fmt.Sprintf("%s", a) != "{1}" {
+		t.Fatalf("read from failed: set modified on error: %s", a)
+	}
+}
@@ -0,0 +1,306 @@
+// Command intset does set arithmetic on integer sets read from files
+// or standard input.
+//
+// Usage:
+//
+//	intset [-o format] command [argument ...]
+//
+// The commands are:
+//
+//	union FILE...           print the union of the sets
+//	intersect FILE...       print the intersection of the sets
+//	diff FILE...            print the first set minus the others
+//	xor FILE...             print the symmetric difference of the sets
+//	complement [FILE]       print the complement of the set
+//	contains FILE N...      exit non-zero unless the set holds every N
+//	subset FILE FILE        exit non-zero unless the first set is a subset
+//	disjoint FILE FILE      exit non-zero unless the sets are disjoint
+//	equal FILE FILE         exit non-zero unless the sets are equal
+//	card [FILE]             print the cardinality of the set
+//	gaps FILE [LO HI]       print the holes of the set, or its gaps
+//	                        from LO to HI
+//	format [FILE]           print the set in the chosen format
+//
+// A FILE of - is standard input, which is also read when a command
+// expecting one set is given no FILE. Sets may be written in any
+// notation accepted by intset.Parse, e.g. one integer per line, lists
+// like 1-5,7 or the form printed by the set format.
+//
+// The output formats are:
+//
+//	set      the set notation, e.g. {1:5, 7, 10:∞}
+//	ranges   a list like 1-5,7
+//	lines    one integer per line
+//
+// The exit status is 0 on success, 1 when a predicate does not hold,
+// and 2 on errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/stianwa/intset"
+)
+
+// errFalse is returned by predicates which do not hold.
+var errFalse = errors.New("predicate does not hold")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("intset", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("o", "set", "output `format`: set, ranges or lines")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: intset [-o format] command [argument ...]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	c := &cli{stdin: stdin, stdout: stdout, format: *format}
+	err := c.command(fs.Arg(0), fs.Args()[1:])
+	if err == errFalse {
+		return 1
+	} else if err != nil {
+		fmt.Fprintf(stderr, "intset: %v\n", err)
+		return 2
+	}
+
+	return 0
+}
+
+// cli holds the state of a command line run.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	format string
+}
+
+// command runs the command name with its arguments.
+func (c *cli) command(name string, args []string) error {
+	switch name {
+	case "union", "intersect", "diff", "xor":
+		sets, err := c.readSets(args, 1)
+		if err != nil {
+			return err
+		}
+		r := sets[0]
+		for _, s := range sets[1:] {
+			switch name {
+			case "union":
+				r = r.Union(s)
+			case "intersect":
+				r = r.Intersect(s)
+			case "diff":
+				r = r.Difference(s)
+			case "xor":
+				r = r.Xor(s)
+			}
+		}
+		return c.write(r)
+	case "complement", "card", "format":
+		if len(args) > 1 {
+			return fmt.Errorf("%s takes at most one set", name)
+		}
+		sets, err := c.readSets(args, 1)
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "complement":
+			return c.write(sets[0].Complement())
+		case "card":
+			if n, inf := sets[0].Cardinality(); inf {
+				fmt.Fprintf(c.stdout, "%c\n", 0x221e)
+			} else {
+				fmt.Fprintf(c.stdout, "%d\n", n)
+			}
+			return nil
+		}
+		return c.write(sets[0])
+	case "contains":
+		if len(args) < 2 {
+			return fmt.Errorf("contains takes a set and one or more integers")
+		}
+		sets, err := c.readSets(args[:1], 1)
+		if err != nil {
+			return err
+		}
+		for _, arg := range args[1:] {
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("invalid integer %q", arg)
+			}
+			if !sets[0].HasInt(n) {
+				return errFalse
+			}
+		}
+		return nil
+	case "subset", "disjoint", "equal":
+		if len(args) != 2 {
+			return fmt.Errorf("%s takes two sets", name)
+		}
+		sets, err := c.readSets(args, 2)
+		if err != nil {
+			return err
+		}
+		a, b := sets[0], sets[1]
+		ok := false
+		switch name {
+		case "subset":
+			ok = a.IsSubsetOf(b)
+		case "disjoint":
+			ok = a.Intersect(b).Equal(intset.New())
+		case "equal":
+			ok = a.Equal(b)
+		}
+		if !ok {
+			return errFalse
+		}
+		return nil
+	case "gaps":
+		if len(args) != 1 && len(args) != 3 {
+			return fmt.Errorf("gaps takes a set and an optional window")
+		}
+		sets, err := c.readSets(args[:1], 1)
+		if err != nil {
+			return err
+		}
+		if len(args) == 3 {
+			lo, err1 := strconv.Atoi(args[1])
+			hi, err2 := strconv.Atoi(args[2])
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("invalid window %s %s", args[1], args[2])
+			}
+			return c.write(sets[0].Gaps(lo, hi))
+		}
+		holes := intset.New()
+		for h := range sets[0].Holes() {
+			holes.AddElements(h)
+		}
+		return c.write(holes)
+	}
+
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// readSets reads the sets in the named files. Standard input is read
+// for files named -, or if fewer than min files are named.
+func (c *cli) readSets(files []string, min int) ([]*intset.IntSet, error) {
+	if len(files) < min {
+		files = append([]string{"-"}, files...)
+	}
+	if len(files) < min {
+		return nil, fmt.Errorf("expected at least %d sets", min)
+	}
+
+	var sets []*intset.IntSet
+	stdin := false
+	for _, name := range files {
+		var b []byte
+		var err error
+		if name == "-" {
+			if stdin {
+				return nil, fmt.Errorf("standard input can only be read once")
+			}
+			stdin = true
+			b, err = io.ReadAll(c.stdin)
+		} else {
+			b, err = os.ReadFile(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := intset.Parse(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		sets = append(sets, s)
+	}
+
+	return sets, nil
+}
+
+// write writes the set in the chosen output format.
+func (c *cli) write(a *intset.IntSet) error {
+	switch c.format {
+	case "set":
+		_, err := fmt.Fprintln(c.stdout, a)
+		return err
+	case "ranges":
+		r, err := formatRanges(a)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.stdout, r)
+		return err
+	case "lines":
+		for e := range a.Elements() {
+			if _, ok := e.Min(); !ok {
+				return intset.ErrInfinite
+			} else if _, ok := e.Max(); !ok {
+				return intset.ErrInfinite
+			}
+		}
+		// written element by element, as the set may be too large
+		// to be held as a slice
+		for e := range a.Elements() {
+			lo, _ := e.Min()
+			hi, _ := e.Max()
+			for n := lo; ; n += e.Stride() {
+				if _, err := fmt.Fprintln(c.stdout, n); err != nil {
+					return err
+				}
+				if n == hi {
+					break
+				}
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format %q", c.format)
+}
+
+// formatRanges returns the set as a list like 1-5,7. Infinite ends
+// are written as -inf and inf.
+func formatRanges(a *intset.IntSet) (string, error) {
+	var ents []string
+	for e := range a.Elements() {
+		if e.Stride() > 1 {
+			return "", fmt.Errorf("progression %s can not be written as a range", e)
+		}
+		lo, lok := e.Min()
+		hi, hok := e.Max()
+		l, h := "-inf", "inf"
+		if lok {
+			l = strconv.Itoa(lo)
+		}
+		if hok {
+			h = strconv.Itoa(hi)
+		}
+		if lok && hok && lo == hi {
+			ents = append(ents, l)
+		} else {
+			ents = append(ents, l+"-"+h)
+		}
+	}
+
+	return strings.Join(ents, ","), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	if err := os.WriteFile(a, []byte("1\n2\n3\n10-20\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("{3:12, 30:∞}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args   []string
+		stdin  string
+		status int
+		output string
+	}{
+		{[]string{"union", a, b}, "", 0, "{1:20, 30:∞}\n"},
+		{[]string{"intersect", a, b}, "", 0, "{3, 10:12}\n"},
+		{[]string{"diff", a, b}, "", 0, "{1:2, 13:20}\n"},
+		{[]string{"xor", a, b}, "", 0, "{1:2, 4:9, 13:20, 30:∞}\n"},
+		{[]string{"-o", "ranges", "diff", a, "-"}, "2,15-30", 0, "1,3,10-14\n"},
+		{[]string{"-o", "ranges", "complement", b}, "", 0, "-inf-2,13-29\n"},
+		{[]string{"-o", "lines", "format"}, "5-7", 0, "5\n6\n7\n"},
+		{[]string{"-o", "lines", "format"}, "0:8:4 20", 0, "0\n4\n8\n20\n"},
+		{[]string{"-o", "lines", "format", b}, "", 2, ""},
+		{[]string{"card", a}, "", 0, "14\n"},
+		{[]string{"card", b}, "", 0, "∞\n"},
+		{[]string{"gaps", a}, "", 0, "{4:9}\n"},
+		{[]string{"gaps", b, "0", "40"}, "", 0, "{0:2, 13:29}\n"},
+		{[]string{"contains", a, "2", "15"}, "", 0, ""},
+		{[]string{"contains", a, "2", "4"}, "", 1, ""},
+		{[]string{"subset", "-", a}, "1,11", 0, ""},
+		{[]string{"subset", a, b}, "", 1, ""},
+		{[]string{"disjoint", a, "-"}, "4-9", 0, ""},
+		{[]string{"equal", a, "-"}, "1:3 10:20", 0, ""},
+		{[]string{"union", "-", "-"}, "1", 2, ""},
+		{[]string{"union", a, filepath.Join(dir, "missing")}, "", 2, ""},
+		{[]string{"format"}, "1 x", 2, ""},
+		{[]string{"frobnicate"}, "", 2, ""},
+		{[]string{}, "", 2, ""},
+	}
+
+	for _, tc := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+		if status != tc.status {
+			t.Fatalf("run %q failed: got status %d, expected %d (%s)", tc.args, status, tc.status, stderr.String())
+		}
+		if status == 0 && stdout.String() != tc.output {
+			t.Fatalf("run %q failed: got %q, expected %q", tc.args, stdout.String(), tc.output)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package intset
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ErrInfinite is returned when an infinite set is converted to a
+// representation which can only hold finite sets.
+var ErrInfinite = errors.New("intset: set is infinite")
+
+// maxAlloc is a conservative limit of the size in bytes of a single
+// allocation, 2 GiB on 32-bit platforms and 16 GiB on 64-bit
+// platforms.
+const maxAlloc = 1 << (31 + 3*(bits.UintSize/64))
+
+// ToSlice returns the integers of the set in ascending order. An
+// error is returned if the set is infinite, or if it holds more than
+// limit integers.
+func (a *IntSet) ToSlice(limit int) ([]int, error) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	c, inf := a.Cardinality()
+	if inf {
+		return nil, ErrInfinite
+	} else if c > uint(limit) {
+		return nil, fmt.Errorf("intset: cardinality %d exceeds limit %d", c, limit)
+	} else if c > maxAlloc/(bits.UintSize/8) {
+		return nil, fmt.Errorf("intset: cardinality %d too large for slice", c)
+	}
+
+	a, err := a.contiguous()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]int, 0, c)
+	for _, e := range a.elements {
+		for i := e.first; ; i++ {
+			ret = append(ret, i)
+			if i == e.last {
+				break
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// FromMap returns a new set holding the keys of m.
+func FromMap(m map[int]struct{}) *IntSet {
+	ns := make([]int, 0, len(m))
+	for n := range m {
+		ns = append(ns, n)
+	}
+
+	return FromInts(ns)
+}
+
+// ToMap returns the integers of the set as keys of a map. An error is
+// returned if the set is infinite, or if it holds more than limit
+// integers.
+func (a *IntSet) ToMap(limit int) (map[int]struct{}, error) {
+	ns, err := a.ToSlice(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[int]struct{}, len(ns))
+	for _, n := range ns {
+		m[n] = struct{}{}
+	}
+
+	return m, nil
+}
+
+// FromBitset returns a new set from the bitset b, where bit i
+// represents the integer offset+i. The bit order is the same as the
+// one used by math/big.Int, i.e. bit i is found in word i/64 at
+// position i%64 counted from the least significant bit. ErrOverflow is
+// returned if a set bit represents an integer beyond the limit of the
+// platform.
+func FromBitset(b []uint64, offset int) (*IntSet, error) {
+	n := &IntSet{}
+	var cur *Element
+	for w, word := range b {
+		for word != 0 {
+			i := bits.TrailingZeros64(word)
+			word &^= 1 << uint(i)
+
+			d := uint(w)*64 + uint(i)
+			if d > uint(intMax)-uint(offset) {
+				return nil, ErrOverflow
+			}
+			v := int(uint(offset) + d)
+			if cur != nil && cur.last+1 == v {
+				cur.last = v
+				continue
+			}
+			cur = &Element{first: v, last: v}
+			n.elements = append(n.elements, cur)
+		}
+	}
+
+	return n, nil
+}
+
+// ToBitset returns the set as a bitset together with the offset of
+// bit 0, which is the smallest integer of the set. The bit order is
+// the same as for FromBitset. An error is returned if the set is
+// infinite, or if the span of the set is too large for a bitset.
+func (a *IntSet) ToBitset() ([]uint64, int, error) {
+	a, err := a.contiguous()
+	if err != nil {
+		return nil, 0, err
+	} else if len(a.elements) == 0 {
+		return nil, 0, nil
+	}
+
+	offset := a.elements[0].first
+	span := uint(a.elements[len(a.elements)-1].last) - uint(offset)
+	if span >= uint(intMax) || span/64 >= maxAlloc/8 {
+		return nil, 0, fmt.Errorf("intset: span %d too large for bitset", span)
+	}
+
+	b := make([]uint64, span/64+1)
+	for _, e := range a.elements {
+		for i := uint(e.first) - uint(offset); ; i++ {
+			b[i/64] |= 1 << (i % 64)
+			if i == uint(e.last)-uint(offset) {
+				break
+			}
+		}
+	}
+
+	return b, offset, nil
+}
+
+// FromRanges returns a new set holding the closed ranges in r. The
+// bounds of each range may be given in any order.
+func FromRanges(r [][2]int) *IntSet {
+	b := &Builder{}
+	for _, p := range r {
+		b.AddRange(p[0], p[1])
+	}
+
+	return b.Build()
+}
+
+// ToRanges returns the set as a list of closed ranges in ascending
+// order. An error is returned if the set is infinite.
+func (a *IntSet) ToRanges() ([][2]int, error) {
+	a, err := a.contiguous()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([][2]int, 0, len(a.elements))
+	for _, e := range a.elements {
+		ret = append(ret, [2]int{e.first, e.last})
+	}
+
+	return ret, nil
+}
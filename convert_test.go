@@ -0,0 +1,135 @@
+package intset
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestToSlice(t *testing.T) {
+	a := New(Range(-2, 1), Int(5), Range(8, 9))
+	got, err := a.ToSlice(10)
+	if err != nil {
+		t.Fatalf("to slice failed: %v", err)
+	}
+	e := []int{-2, -1, 0, 1, 5, 8, 9}
+	if !reflect.DeepEqual(got, e) {
+		t.Fatalf("to slice failed: got %v, expected %v", got, e)
+	}
+
+	if _, err := a.ToSlice(6); err == nil {
+		t.Fatalf("to slice failed: expected limit error for %s", a)
+	}
+	if _, err := New(PosInf(1)).ToSlice(10); err != ErrInfinite {
+		t.Fatalf("to slice failed: got %v, expected %v", err, ErrInfinite)
+	}
+	if _, err := New(Range(0, intMax/2)).ToSlice(intMax); err == nil {
+		t.Fatalf("to slice failed: expected size error for %s", a)
+	}
+}
+
+func TestMap(t *testing.T) {
+	m := map[int]struct{}{3: {}, 1: {}, 2: {}, 10: {}}
+	a := FromMap(m)
+	e := "{1:3, 10}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from map failed: got %s, expected %s", a, e)
+	}
+
+	got, err := a.ToMap(10)
+	if err != nil {
+		t.Fatalf("to map failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Fatalf("to map failed: got %v, expected %v", got, m)
+	}
+
+	if _, err := New(NegInf(1)).ToMap(10); err != ErrInfinite {
+		t.Fatalf("to map failed: got %v, expected %v", err, ErrInfinite)
+	}
+	if _, err := New(Range(0, intMax/2)).ToMap(intMax); err == nil {
+		t.Fatalf("to map failed: expected size error")
+	}
+}
+
+func TestBitset(t *testing.T) {
+	a := New(Range(-3, 2), Int(61), Range(64, 130))
+	b, offset, err := a.ToBitset()
+	if err != nil {
+		t.Fatalf("to bitset failed: %v", err)
+	}
+	if offset != -3 {
+		t.Fatalf("to bitset failed: got offset %d, expected -3", offset)
+	}
+
+	// Compare the bit order with math/big.
+	var bi big.Int
+	for _, n := range []int{-3, -2, -1, 0, 1, 2, 61} {
+		bi.SetBit(&bi, n+3, 1)
+	}
+	for n := 64; n <= 130; n++ {
+		bi.SetBit(&bi, n+3, 1)
+	}
+	for i := 0; i < len(b)*64; i++ {
+		got := uint(b[i/64]>>uint(i%64)) & 1
+		if got != bi.Bit(i) {
+			t.Fatalf("to bitset failed: bit %d is %d, expected %d", i, got, bi.Bit(i))
+		}
+	}
+
+	c, err := FromBitset(b, offset)
+	if err != nil || !c.Equal(a) {
+		t.Fatalf("from bitset failed: got %s, %v, expected %s", c, err, a)
+	}
+
+	if c, err := FromBitset([]uint64{1, 1}, intMax-10); err != ErrOverflow {
+		t.Fatalf("from bitset failed: got %s, %v, expected %v", c, err, ErrOverflow)
+	}
+	if c, err := FromBitset([]uint64{1 << 10, 0}, intMax-10); err != nil || !c.Equal(New(Int(intMax))) {
+		t.Fatalf("from bitset failed: got %s, %v, expected %s", c, err, New(Int(intMax)))
+	}
+	if c, err := FromBitset([]uint64{1, 1 << 63}, intMin); err != nil || !c.Equal(New(Int(intMin), Int(intMin+127))) {
+		t.Fatalf("from bitset failed: got %s, %v", c, err)
+	}
+
+	if _, _, err := New(All()).ToBitset(); err != ErrInfinite {
+		t.Fatalf("to bitset failed: got %v, expected %v", err, ErrInfinite)
+	}
+	if _, _, err := New(Int(0), Int(intMax/2)).ToBitset(); err == nil {
+		t.Fatalf("to bitset failed: expected size error")
+	}
+}
+
+func TestRanges(t *testing.T) {
+	a := FromRanges([][2]int{{10, 5}, {1, 2}, {3, 3}, {20, 30}})
+	e := "{1:3, 5:10, 20:30}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from ranges failed: got %s, expected %s", a, e)
+	}
+
+	got, err := a.ToRanges()
+	if err != nil {
+		t.Fatalf("to ranges failed: %v", err)
+	}
+	er := [][2]int{{1, 3}, {5, 10}, {20, 30}}
+	if !reflect.DeepEqual(got, er) {
+		t.Fatalf("to ranges failed: got %v, expected %v", got, er)
+	}
+
+	if _, err := New(PosInf(0)).ToRanges(); err != ErrInfinite {
+		t.Fatalf("to ranges failed: got %v, expected %v", err, ErrInfinite)
+	}
+}
+
+func TestToSliceStep(t *testing.T) {
+	a := New(Step(0, 10, 5), Int(1))
+	got, err := a.ToSlice(10)
+	if err != nil {
+		t.Fatalf("to slice failed: %v", err)
+	}
+	e := []int{0, 1, 5, 10}
+	if !reflect.DeepEqual(got, e) {
+		t.Fatalf("to slice failed: got %v, expected %v", got, e)
+	}
+}
@@ -0,0 +1,212 @@
+package intset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCPUList returns a new set from a list in the format used by the
+// Linux kernel for CPU and NUMA node lists, e.g. the contents of
+// /sys/devices/system/cpu/online or cpuset.cpus, and by taskset:
+//
+//	0-3,8-11,16
+//
+// Ranges may be followed by :used/group, selecting the first used
+// CPUs of every group of group CPUs, e.g. 0-15:2/4 is 0-1,4-5,8-9,12-13.
+// Surrounding white space is ignored, and an empty list is the empty
+// set.
+func ParseCPUList(s string) (*IntSet, error) {
+	s = strings.TrimSpace(s)
+	b := &Builder{}
+	if s == "" {
+		return b.Build(), nil
+	}
+
+	for _, item := range strings.Split(s, ",") {
+		if err := parseCPUItem(b, item); err != nil {
+			return nil, fmt.Errorf("intset: invalid cpu list item %q: %v", item, err)
+		}
+	}
+
+	return b.Build(), nil
+}
+
+// parseCPUItem adds the CPUs of one item of a CPU list to b.
+func parseCPUItem(b *Builder, item string) error {
+	r, groups, grouped := strings.Cut(item, ":")
+	lo, hi, ranged := strings.Cut(r, "-")
+	first, err := parseCPU(lo)
+	if err != nil {
+		return err
+	}
+	last := first
+	if ranged {
+		if last, err = parseCPU(hi); err != nil {
+			return err
+		} else if last < first {
+			return fmt.Errorf("reversed range")
+		}
+	}
+	if !grouped {
+		b.AddRange(first, last)
+		return nil
+	}
+
+	u, g, ok := strings.Cut(groups, "/")
+	if !ok {
+		return fmt.Errorf("missing group size")
+	}
+	used, err := parseCPU(u)
+	if err != nil {
+		return err
+	}
+	size, err := parseCPU(g)
+	if err != nil {
+		return err
+	} else if used == 0 || size == 0 || used > size {
+		return fmt.Errorf("invalid group %d/%d", used, size)
+	}
+
+	for i := first; i <= last; i += size {
+		if i > last-used+1 {
+			b.AddRange(i, last)
+		} else {
+			b.AddRange(i, i+used-1)
+		}
+		if i > intMax-size {
+			break
+		}
+	}
+
+	return nil
+}
+
+// parseCPU parses a decimal CPU number, which must not be signed.
+func parseCPU(s string) (int, error) {
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("bad number %q", s)
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %q", s)
+	}
+
+	return n, nil
+}
+
+// FormatCPUList returns the set as a list in the format read by
+// ParseCPUList, without groups, e.g. 0-3,8-11,16. An error is returned
+// if the set is infinite or holds negative integers.
+func FormatCPUList(a *IntSet) (string, error) {
+	ranges, err := cpuRanges(a)
+	if err != nil {
+		return "", err
+	}
+
+	var ents []string
+	for _, r := range ranges {
+		if r[0] == r[1] {
+			ents = append(ents, strconv.Itoa(r[0]))
+		} else {
+			ents = append(ents, fmt.Sprintf("%d-%d", r[0], r[1]))
+		}
+	}
+
+	return strings.Join(ents, ","), nil
+}
+
+// ParseCPUMask returns a new set from a hexadecimal mask of
+// comma-separated 32-bit words with the most significant word first,
+// as used by Cpus_allowed in /proc/*/status and by cpumap files in
+// sysfs, e.g. ff,00000003 is 0-1,32-39.
+func ParseCPUMask(s string) (*IntSet, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("intset: empty cpu mask")
+	}
+
+	words := strings.Split(s, ",")
+	b := &Builder{}
+	for i, w := range words {
+		if w == "" || len(w) > 8 {
+			return nil, fmt.Errorf("intset: invalid cpu mask word %q", w)
+		}
+		v, err := strconv.ParseUint(w, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("intset: invalid cpu mask word %q", w)
+		}
+
+		base := (len(words) - 1 - i) * 32
+		for bit := 0; v != 0; bit, v = bit+1, v>>1 {
+			if v&1 == 1 {
+				b.Add(base + bit)
+			}
+		}
+	}
+
+	return b.Build(), nil
+}
+
+// FormatCPUMask returns the set as a mask in the format read by
+// ParseCPUMask. Every word is written with 8 hexadecimal digits, and
+// the mask holds at least nbits bits, e.g. the number of possible
+// CPUs. An error is returned if the set is infinite, holds negative
+// integers, or if the mask is too large to allocate.
+func FormatCPUMask(a *IntSet, nbits int) (string, error) {
+	ranges, err := cpuRanges(a)
+	if err != nil {
+		return "", err
+	}
+
+	size := uint(0)
+	if nbits > 0 {
+		size = uint(nbits)
+	}
+	if len(ranges) > 0 && uint(ranges[len(ranges)-1][1]) >= size {
+		size = uint(ranges[len(ranges)-1][1]) + 1
+	}
+	n := (size + 31) / 32
+	if n == 0 {
+		n = 1
+	} else if n > maxAlloc/9 {
+		// every word is written with 9 bytes
+		return "", fmt.Errorf("intset: cpu mask of %d bits too large", size)
+	}
+
+	words := make([]uint32, n)
+	for _, r := range ranges {
+		for i := r[0]; ; i++ {
+			words[i/32] |= 1 << (i % 32)
+			if i == r[1] {
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(int(9*n - 1))
+	for i := len(words) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "%08x", words[i])
+		if i > 0 {
+			b.WriteByte(',')
+		}
+	}
+
+	return b.String(), nil
+}
+
+// cpuRanges returns the ranges of the set, which must be finite and
+// not hold negative integers.
+func cpuRanges(a *IntSet) ([][2]int, error) {
+	ranges, err := a.ToRanges()
+	if err != nil {
+		return nil, err
+	}
+	if len(ranges) > 0 && ranges[0][0] < 0 {
+		return nil, fmt.Errorf("intset: negative cpu %d", ranges[0][0])
+	}
+
+	return ranges, nil
+}
@@ -0,0 +1,112 @@
+package intset
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	tests := map[string]string{
+		"0-3,8-11,16\n": "{0:3, 8:11, 16}",
+		"0":             "{0}",
+		"":              "{∅}",
+		"\n":            "{∅}",
+		"4-7,0-5":       "{0:7}",
+		"0-15:2/4":      "{0:1, 4:5, 8:9, 12:13}",
+		"0-14:3/4":      "{0:2, 4:6, 8:10, 12:14}",
+		"0-13:3/4,20":   "{0:2, 4:6, 8:10, 12:13, 20}",
+	}
+
+	for s, e := range tests {
+		a, err := ParseCPUList(s)
+		if err != nil {
+			t.Fatalf("parse cpu list %q failed: %v", s, err)
+		}
+		if fmt.Sprintf("%s", a) != e {
+			t.Fatalf("parse cpu list %q failed: got %s, expected %s", s, a, e)
+		}
+	}
+
+	for _, s := range []string{"-1", "1,,2", "3-1", "1-", "a", "0-7:2", "0-7:0/4", "0-7:5/4", "+1", "1 - 2"} {
+		if _, err := ParseCPUList(s); err == nil {
+			t.Fatalf("parse cpu list %q failed: got no error", s)
+		}
+	}
+}
+
+func TestFormatCPUList(t *testing.T) {
+	a := New(Range(0, 3), Range(8, 11), Int(16))
+	if s, err := FormatCPUList(a); err != nil || s != "0-3,8-11,16" {
+		t.Fatalf("format cpu list %s failed: got %q, %v", a, s, err)
+	}
+
+	a = New(Step(0, 6, 2))
+	if s, err := FormatCPUList(a); err != nil || s != "0,2,4,6" {
+		t.Fatalf("format cpu list %s failed: got %q, %v", a, s, err)
+	}
+
+	if s, err := FormatCPUList(New()); err != nil || s != "" {
+		t.Fatalf("format cpu list of empty set failed: got %q, %v", s, err)
+	}
+	if _, err := FormatCPUList(New(PosInf(4))); !errors.Is(err, ErrInfinite) {
+		t.Fatalf("format cpu list of infinite set failed: got %v", err)
+	}
+	if _, err := FormatCPUList(New(Range(-1, 3))); err == nil {
+		t.Fatalf("format cpu list of negative cpu failed: got no error")
+	}
+}
+
+func TestCPUMask(t *testing.T) {
+	tests := []struct {
+		mask  string
+		nbits int
+		set   string
+	}{
+		{"00000000", 0, "{∅}"},
+		{"0000000f", 4, "{0:3}"},
+		{"000000ff,00000003", 64, "{0:1, 32:39}"},
+		{"00000000,00000000,80000001", 96, "{0, 31}"},
+		{"00000001,00000000", 0, "{32}"},
+	}
+
+	for _, tc := range tests {
+		a, err := ParseCPUMask(tc.mask)
+		if err != nil {
+			t.Fatalf("parse cpu mask %q failed: %v", tc.mask, err)
+		}
+		if fmt.Sprintf("%s", a) != tc.set {
+			t.Fatalf("parse cpu mask %q failed: got %s, expected %s", tc.mask, a, tc.set)
+		}
+		if s, err := FormatCPUMask(a, tc.nbits); err != nil || s != tc.mask {
+			t.Fatalf("format cpu mask %s failed: got %q, %v, expected %q", a, s, err, tc.mask)
+		}
+	}
+
+	if a, err := ParseCPUMask("ff,3\n"); err != nil || fmt.Sprintf("%s", a) != "{0:1, 32:39}" {
+		t.Fatalf("parse cpu mask of short words failed: got %s, %v", a, err)
+	}
+	for _, s := range []string{"", "ff,,3", "123456789", "xyz", "-1"} {
+		if _, err := ParseCPUMask(s); err == nil {
+			t.Fatalf("parse cpu mask %q failed: got no error", s)
+		}
+	}
+	if _, err := FormatCPUMask(New(All()), 64); !errors.Is(err, ErrInfinite) {
+		t.Fatalf("format cpu mask of infinite set failed: got %v", err)
+	}
+	if bits.UintSize == 64 {
+		// masks of 32-bit platforms fit maxAlloc
+		for _, a := range []*IntSet{New(Int(intMax)), New(Int(0), Int(intMax/(1<<23)))} {
+			if s, err := FormatCPUMask(a, 0); err == nil {
+				t.Fatalf("format cpu mask of %s failed: got %d bytes, expected an error", a, len(s))
+			}
+		}
+		if s, err := FormatCPUMask(New(), intMax); err == nil {
+			t.Fatalf("format cpu mask of %d bits failed: got %d bytes, expected an error", intMax, len(s))
+		}
+	}
+	if s, err := FormatCPUMask(New(Int(0)), -5); err != nil || s != "00000001" {
+		t.Fatalf("format cpu mask of -5 bits failed: got %q, %v", s, err)
+	}
+}
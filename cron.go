@@ -0,0 +1,139 @@
+package intset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CronField describes a field of a cron expression: its domain from
+// Min to Max and the names which may be used for its values, where
+// Names[0] is the name of Min.
+type CronField struct {
+	Name     string
+	Min, Max int
+	Names    []string
+
+	// sunday7 allows 7 as an alias of 0, as for days of the week
+	sunday7 bool
+}
+
+// The fields of a cron expression.
+var (
+	CronMinute     = CronField{Name: "minute", Min: 0, Max: 59}
+	CronHour       = CronField{Name: "hour", Min: 0, Max: 23}
+	CronDayOfMonth = CronField{Name: "day of month", Min: 1, Max: 31}
+	CronMonth      = CronField{Name: "month", Min: 1, Max: 12,
+		Names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}}
+	CronDayOfWeek = CronField{Name: "day of week", Min: 0, Max: 6,
+		Names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}, sunday7: true}
+)
+
+// Parse returns a new set from the cron syntax of the field, i.e. a
+// list of items separated by commas, where every item is one of:
+//
+//	n       a single value
+//	a-b     a range
+//	*       every value of the field, also written ?
+//	a-b/s   every s value from a to b
+//	a/s     every s value from a to the end of the field
+//	*/s     every s value of the field
+//
+// Values may be given by name, e.g. MON-FRI, in any case. For days of
+// the week, 7 is Sunday as well as 0. Values outside the domain of the
+// field are errors.
+func (f CronField) Parse(s string) (*IntSet, error) {
+	n := New()
+	for _, item := range strings.Split(strings.TrimSpace(s), ",") {
+		e, err := f.parseItem(item)
+		if err != nil {
+			return nil, fmt.Errorf("intset: invalid %s %q: %v", f.Name, item, err)
+		}
+		n.insertElement(e)
+	}
+
+	if f.sunday7 && n.HasInt(7) {
+		n.removeElement(Int(7))
+		n.insertElement(Int(0))
+	}
+
+	return n, nil
+}
+
+// parseItem returns the element of an item of a cron field.
+func (f CronField) parseItem(item string) (*Element, error) {
+	r, step, stepped := strings.Cut(item, "/")
+	stride := 1
+	if stepped {
+		var err error
+		if stride, err = strconv.Atoi(step); err != nil || stride < 1 {
+			return nil, fmt.Errorf("bad step %q", step)
+		}
+	}
+
+	max := f.Max
+	if f.sunday7 {
+		max = 7
+	}
+
+	var first, last int
+	if r == "*" || r == "?" {
+		first, last = f.Min, f.Max
+	} else {
+		lo, hi, ranged := strings.Cut(r, "-")
+		var err error
+		if first, err = f.value(lo, max); err != nil {
+			return nil, err
+		}
+		last = first
+		if ranged {
+			if last, err = f.value(hi, max); err != nil {
+				return nil, err
+			} else if last < first {
+				return nil, fmt.Errorf("reversed range")
+			}
+		} else if stepped {
+			// 7/s for days of the week is Sunday alone
+			if last = f.Max; first > last {
+				last = first
+			}
+		}
+	}
+
+	return Step(first, last, stride), nil
+}
+
+// value returns the value of a number or name in the field, which must
+// be within the domain up to max.
+func (f CronField) value(s string, max int) (int, error) {
+	for i, name := range f.Names {
+		if strings.EqualFold(s, name) {
+			return f.Min + i, nil
+		}
+	}
+
+	if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("bad value %q", s)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.Min || n > max {
+		return 0, fmt.Errorf("%s is outside %d-%d", s, f.Min, max)
+	}
+
+	return n, nil
+}
+
+// Next returns the value of the set within the field following n. If
+// no value follows n, it wraps around to the first value of the set
+// within the field and returns true, telling the caller to carry over
+// to the next larger field. If the set holds no value within the
+// field, n and true are returned.
+func (f CronField) Next(a *IntSet, n int) (int, bool) {
+	if next, ok := a.NextAfter(n); ok && next <= f.Max {
+		return next, false
+	} else if next, ok := a.NextAfter(f.Min - 1); ok && next <= f.Max {
+		return next, true
+	}
+
+	return n, true
+}
@@ -0,0 +1,91 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCronParse(t *testing.T) {
+	tests := []struct {
+		field CronField
+		s     string
+		e     string
+	}{
+		{CronMinute, "*/15", "{0:45:15}"},
+		{CronMinute, "*", "{0:59}"},
+		{CronMinute, "5,10-12,30/10", "{5, 10:12, 30:50:10}"},
+		{CronHour, "1-5", "{1:5}"},
+		{CronHour, "0-23/6", "{0:18:6}"},
+		{CronDayOfMonth, "*/10", "{1:31:10}"},
+		{CronMonth, "jan,MAR-may", "{1, 3:5}"},
+		{CronMonth, "FEB/3", "{2:11:3}"},
+		{CronDayOfWeek, "MON-FRI", "{1:5}"},
+		{CronDayOfWeek, "5-7", "{0, 5:6}"},
+		{CronDayOfWeek, "sun,7", "{0}"},
+		{CronDayOfWeek, "7/2", "{0}"},
+		{CronDayOfWeek, "5-7/2", "{0, 5}"},
+		{CronDayOfWeek, "?", "{0:6}"},
+	}
+
+	for _, tc := range tests {
+		a, err := tc.field.Parse(tc.s)
+		if err != nil {
+			t.Fatalf("parse %s %q failed: %v", tc.field.Name, tc.s, err)
+		}
+		if fmt.Sprintf("%s", a) != tc.e {
+			t.Fatalf("parse %s %q failed: got %s, expected %s", tc.field.Name, tc.s, a, tc.e)
+		}
+	}
+
+	invalid := []struct {
+		field CronField
+		s     string
+	}{
+		{CronMinute, "60"},
+		{CronMinute, "*/0"},
+		{CronMinute, "10-5"},
+		{CronMinute, "1,,2"},
+		{CronMinute, ""},
+		{CronMinute, "-5"},
+		{CronHour, "MON"},
+		{CronDayOfMonth, "0"},
+		{CronMonth, "13"},
+		{CronDayOfWeek, "8"},
+		{CronDayOfWeek, "MON-FRI/x"},
+	}
+
+	for _, tc := range invalid {
+		if a, err := tc.field.Parse(tc.s); err == nil {
+			t.Fatalf("parse %s %q failed: got %s, expected error", tc.field.Name, tc.s, a)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	minutes, _ := CronMinute.Parse("*/15")
+	tests := []struct {
+		n, next int
+		wrapped bool
+	}{
+		{-1, 0, false},
+		{0, 15, false},
+		{14, 15, false},
+		{44, 45, false},
+		{45, 0, true},
+		{59, 0, true},
+	}
+
+	for _, tc := range tests {
+		if next, wrapped := CronMinute.Next(minutes, tc.n); next != tc.next || wrapped != tc.wrapped {
+			t.Fatalf("next of %s after %d failed: got %d, %t, expected %d, %t", minutes, tc.n, next, wrapped, tc.next, tc.wrapped)
+		}
+	}
+
+	days, _ := CronDayOfMonth.Parse("10-12")
+	if next, wrapped := CronDayOfMonth.Next(days, 12); next != 10 || !wrapped {
+		t.Fatalf("next of %s after 12 failed: got %d, %t", days, next, wrapped)
+	}
+	if next, wrapped := CronDayOfMonth.Next(New(Int(40)), 5); next != 5 || !wrapped {
+		t.Fatalf("next of {40} after 5 failed: got %d, %t", next, wrapped)
+	}
+}
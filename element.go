@@ -11,12 +11,15 @@ type Element struct {
 	posinf bool
 	first  int
 	last   int
+	stride int
 }
 
 // String returns the element set in a human readable form, in
 // compliance with the fmt.Stringer interface.
 func (e *Element) String() string {
-	if e.all {
+	if e.stride > 1 {
+		return e.stepString()
+	} else if e.all {
 		return fmt.Sprintf("-%c:%c", 0x221e, 0x221e)
 	} else if e.neginf {
 		return fmt.Sprintf("-%c:%d", 0x221e, e.first)
@@ -56,6 +59,68 @@ func PosInf(n int) *Element {
 	return &Element{first: n, last: n, posinf: true}
 }
 
+// Min returns the smallest integer of the element, and false if the
+// element spans to -∞.
+func (e *Element) Min() (int, bool) {
+	lo, _, neg, _ := e.bounds()
+	return lo, !neg
+}
+
+// Max returns the largest integer of the element, and false if the
+// element spans to ∞.
+func (e *Element) Max() (int, bool) {
+	_, hi, _, pos := e.bounds()
+	return hi, !pos
+}
+
+// Stride returns the distance between the integers of the element,
+// which is 1 for ranges.
+func (e *Element) Stride() int {
+	return e.step()
+}
+
+// Cardinality returns the number of integers in the element, and true
+// if the element is infinite, in the same manner as
+// IntSet.Cardinality.
+func (e *Element) Cardinality() (uint, bool) {
+	if e.inf() {
+		return 0, true
+	}
+	c := e.count()
+	if c == 0 {
+		// the element spans every integer of the platform
+		return 0, true
+	}
+	return c, false
+}
+
+// bounds returns the lower and upper limits of the element. The
+// limits are only valid when the corresponding infinite flag is
+// false.
+func (e *Element) bounds() (int, int, bool, bool) {
+	if e.all {
+		return 0, 0, true, true
+	} else if e.neginf {
+		return 0, e.first, true, false
+	} else if e.posinf {
+		return e.first, 0, false, true
+	}
+	return e.first, e.last, false, false
+}
+
+// fromBounds returns an element from lower and upper limits, as
+// returned by bounds.
+func fromBounds(lo, hi int, neginf, posinf bool) *Element {
+	if neginf && posinf {
+		return All()
+	} else if neginf {
+		return NegInf(hi)
+	} else if posinf {
+		return PosInf(lo)
+	}
+	return Range(lo, hi)
+}
+
 // inf Returns true if element set has an infinite flag set
 func (e *Element) inf() bool {
 	return e.all || e.neginf || e.posinf
@@ -63,20 +128,15 @@ func (e *Element) inf() bool {
 
 // isAdjacent Returns true if the two element sets are adjacent.
 func (e *Element) isAdjacent(o *Element) bool {
-	return e.all ||
-		o.all ||
-
-		(e.neginf && !o.inf() && o.first == e.first+1) ||
-		(o.neginf && !e.inf() && e.first == o.first+1) ||
-
-		(e.posinf && !o.inf() && e.first-1 == o.last) ||
-		(o.posinf && !e.inf() && o.first-1 == e.last) ||
+	if e.all || o.all {
+		return true
+	}
 
-		(e.posinf && o.neginf && o.first+1 == e.first) ||
-		(o.posinf && e.neginf && e.first+1 == o.first) ||
+	elo, ehi, eneg, epos := e.bounds()
+	olo, ohi, oneg, opos := o.bounds()
 
-		(!e.inf() && !o.inf() && o.first == e.last+1) ||
-		(!o.inf() && !e.inf() && e.first == o.last+1)
+	return (!epos && !oneg && ehi != intMax && olo == ehi+1) ||
+		(!opos && !eneg && ohi != intMax && elo == ohi+1)
 }
 
 // isOverlapping Returns true if the two element sets are overlapping.
@@ -96,6 +156,11 @@ func (e *Element) isOverlapping(o *Element) bool {
 
 // isEqual Returns true if the two element sets are equal.
 func (e *Element) isEqual(o *Element) bool {
+	if e.stride != o.stride {
+		return false
+	} else if e.stride > 1 && e.all {
+		return o.all && e.first == o.first
+	}
 	return (e.all && o.all) ||
 		(e.neginf && o.neginf && e.first == o.first) ||
 		(e.posinf && o.posinf && e.first == o.first) ||
@@ -120,66 +185,35 @@ func (e *Element) isWithin(o *Element) bool {
 // elements e and o. Note! The function does not check for overlap,
 // this must be done prior to calling this function.
 func (e *Element) join(o *Element) *Element {
-	neg, min := minInt(e, o)
-	pos, max := maxInt(e, o)
+	elo, ehi, eneg, epos := e.bounds()
+	olo, ohi, oneg, opos := o.bounds()
 
-	if neg < 0 && pos < 0 {
-		return All()
-	} else if neg < 0 {
-		return NegInf(max)
-	} else if pos < 0 {
-		return PosInf(min)
-	}
-	return Range(min, max)
+	return fromBounds(smallestOf(elo, olo), largestOf(ehi, ohi), eneg || oneg, epos || opos)
 }
 
-// remove returns a list of element sets for removine set b from e.
+// remove returns a list of element sets for removing set o from e.
 func (e *Element) remove(o *Element) []*Element {
+	if e.stride > 1 || o.stride > 1 {
+		return e.removeStep(o)
+	}
+
 	var ret []*Element
 
-	if e.isEqual(o) || o.all {
-		return ret
-	} else if o.isSuper(e) || !e.isOverlapping(o) {
+	if !e.isOverlapping(o) {
 		ret = append(ret, e)
 		return ret
 	}
 
-	if e.inf() || o.inf() {
-		if e.all && o.neginf {
-			ret = append(ret, &Element{first: o.first + 1, posinf: true})
-		} else if e.all && o.posinf {
-			ret = append(ret, &Element{first: o.first - 1, neginf: true})
-		} else if e.posinf && o.posinf {
-			ret = append(ret, &Element{first: e.first, last: o.first - 1})
-		} else if e.posinf && o.neginf {
-			ret = append(ret, &Element{first: o.first + 1, posinf: true})
-		} else if e.neginf && o.neginf {
-			ret = append(ret, &Element{first: o.first + 1, last: e.first})
-		} else if e.neginf && o.posinf {
-			ret = append(ret, &Element{first: o.first - 1, neginf: true})
-		} else if e.posinf && !o.inf() {
-			ret = append(ret, &Element{first: o.last + 1, posinf: true})
-		} else if e.neginf && !o.inf() {
-			ret = append(ret, &Element{first: o.first - 1, neginf: true})
-		} else if !e.inf() && o.posinf {
-			ret = append(ret, &Element{first: e.first, last: o.first - 1})
-		} else if !e.inf() && o.neginf {
-			ret = append(ret, &Element{first: o.first + 1, last: e.last})
-		} else if e.all && !o.inf() {
-			ret = append(ret, &Element{first: o.first - 1, neginf: true})
-			ret = append(ret, &Element{first: o.last + 1, posinf: true})
-		} else {
-			fmt.Printf("Here 200 a:%q -b:%q\n", e, o)
-		}
-	} else if o.isWithin(e) {
-		ret = append(ret, &Element{first: e.first, last: o.first - 1})
-		ret = append(ret, &Element{first: o.last + 1, last: e.last})
-	} else {
-		if e.first >= o.first {
-			ret = append(ret, &Element{first: o.last + 1, last: e.last})
-		} else {
-			ret = append(ret, &Element{first: e.first, last: o.first - 1})
-		}
+	elo, ehi, eneg, epos := e.bounds()
+	olo, ohi, oneg, opos := o.bounds()
+
+	// the part of e below o, where nothing is below intMin
+	if !oneg && olo != intMin && (eneg || elo < olo) {
+		ret = append(ret, fromBounds(elo, olo-1, eneg, false))
+	}
+	// the part of e above o, where nothing is above intMax
+	if !opos && ohi != intMax && (epos || ehi > ohi) {
+		ret = append(ret, fromBounds(ohi+1, ehi, false, epos))
 	}
 
 	return ret
@@ -188,98 +222,34 @@ func (e *Element) remove(o *Element) []*Element {
 // intersect returns a list of element sets from intersecting two
 // element sets.
 func (e *Element) intersect(o *Element) []*Element {
-	var ret []*Element
-
-	if e.isEqual(o) || o.all {
-		ret = append(ret, e)
-		return ret
-	} else if e.isSuper(o) {
-		ret = append(ret, o)
-		return ret
-	} else if o.isSuper(e) {
-		ret = append(ret, e)
-		return ret
-	} else if !e.isOverlapping(o) {
-		return ret
+	if e.stride > 1 || o.stride > 1 {
+		return e.intersectStep(o)
 	}
 
-	if e.inf() || o.inf() {
-		if e.all && o.neginf {
-			ret = append(ret, o)
-		} else if e.all && o.posinf {
-			ret = append(ret, o)
-		} else if e.posinf && o.posinf {
-			ret = append(ret, &Element{first: largestOf(e.first, o.first), posinf: true})
-		} else if e.posinf && o.neginf {
-			ret = append(ret, &Element{first: e.first, last: o.first})
-		} else if e.neginf && o.neginf {
-			ret = append(ret, &Element{first: smallestOf(e.first, o.first), neginf: true})
-		} else if e.neginf && o.posinf {
-			ret = append(ret, &Element{first: o.first, last: e.last})
-		} else if e.posinf && !o.inf() {
-			ret = append(ret, &Element{first: largestOf(e.first, o.first), last: o.last})
-		} else if e.neginf && !o.inf() {
-			ret = append(ret, &Element{first: o.first, last: smallestOf(e.first, o.last)})
-		} else if !e.inf() && o.posinf {
-			ret = append(ret, &Element{first: largestOf(e.first, o.first), last: e.last})
-		} else if !e.inf() && o.neginf {
-			ret = append(ret, &Element{first: e.first, last: smallestOf(e.last, o.first)})
-		}
-	} else {
-		ret = append(ret, &Element{first: largestOf(e.first, o.first), last: smallestOf(e.last, o.last)})
-	}
-
-	return ret
-}
+	var ret []*Element
 
-// Returns the minimum range value of two ranges. Returns two
-// integers: 0 or neginf if negative infinitive and the minInt value of a
-// and b unless negative infinitive.
-func minInt(e, o *Element) (int, int) {
-	if e.all || o.all || e.neginf || o.neginf || e.posinf || o.posinf {
-		if e.neginf || o.neginf || e.all || o.all {
-			return -1, 0
-		} else if e.posinf && o.posinf {
-			if e.first < o.first {
-				return 0, e.first
-			}
-			return 0, o.first
-		} else if e.posinf {
-			return 0, o.first
-		}
-		return 0, e.first
-	}
+	elo, ehi, eneg, epos := e.bounds()
+	olo, ohi, oneg, opos := o.bounds()
 
-	if e.first < o.first {
-		return 0, e.first
+	lo, neg := largestOf(elo, olo), eneg && oneg
+	if eneg {
+		lo = olo
+	} else if oneg {
+		lo = elo
 	}
-	return 0, o.first
-}
-
-// Returns the upper limit of two ranges. The function returns two
-// integers. The first is set to posinf if the maximum point is
-// positive infinite. The second is set to the maxumum point unless the
-// first is set to posinf.
-func maxInt(e, o *Element) (int, int) {
-	if e.all || o.all || e.neginf || o.neginf || e.posinf || o.posinf {
-		if e.posinf || o.posinf || e.all || o.all {
-			return -1, 0
-		} else if e.neginf && o.neginf {
-			if e.first > o.first {
-				return 0, e.first
-			}
-			return 0, o.first
-		} else if e.neginf {
-			return 0, o.last
-		}
-		return 0, e.last
+	hi, pos := smallestOf(ehi, ohi), epos && opos
+	if epos {
+		hi = ohi
+	} else if opos {
+		hi = ehi
 	}
 
-	if e.last > o.last {
-		return 0, e.last
+	if !neg && !pos && lo > hi {
+		return ret
 	}
+	ret = append(ret, fromBounds(lo, hi, neg, pos))
 
-	return 0, o.last
+	return ret
 }
 
 func smallestOf(a, b int) int {
@@ -51,6 +51,52 @@ func TestRangeJoinPosInf(t *testing.T) {
 	}
 }
 
+func TestRangeJoinNegInfBoth(t *testing.T) {
+	tests := []struct {
+		a, b *Element
+		e    string
+	}{
+		{NegInf(-10), NegInf(5), "-∞:5"},
+		{NegInf(5), NegInf(-10), "-∞:5"},
+		{Range(-9, 1), NegInf(-10), "-∞:1"},
+		{Range(-20, 1), NegInf(-10), "-∞:1"},
+		{Range(-20, -15), NegInf(-10), "-∞:-10"},
+		{NegInf(-10), Range(-20, -15), "-∞:-10"},
+		{NegInf(0), PosInf(1), "-∞:∞"},
+		{PosInf(1), NegInf(0), "-∞:∞"},
+		{NegInf(0), All(), "-∞:∞"},
+	}
+
+	for _, tc := range tests {
+		if fmt.Sprintf("%s", tc.a.join(tc.b)) != tc.e {
+			t.Fatalf("joining range: %q and %q gave %q, expected %q", tc.a, tc.b, tc.a.join(tc.b), tc.e)
+		}
+	}
+}
+
+func TestRangeIntersectAll(t *testing.T) {
+	tests := []struct {
+		a, b *Element
+		e    string
+	}{
+		{All(), Range(-5, 5), "-5:5"},
+		{Range(-5, 5), All(), "-5:5"},
+		{All(), NegInf(3), "-∞:3"},
+		{PosInf(3), All(), "3:∞"},
+		{All(), All(), "-∞:∞"},
+	}
+
+	for _, tc := range tests {
+		var s []string
+		for _, r := range tc.a.intersect(tc.b) {
+			s = append(s, fmt.Sprintf("%s", r))
+		}
+		if got := strings.Join(s, ", "); got != tc.e {
+			t.Fatalf("intersecting range: %q and %q gave %q, expected %q", tc.a, tc.b, got, tc.e)
+		}
+	}
+}
+
 func TestRangeRemovePosInf(t *testing.T) {
 	a := NegInf(10)
 	b := PosInf(5)
@@ -90,12 +136,53 @@ func TestRangeRemovePosInfN(t *testing.T) {
 		s = append(s, fmt.Sprintf("%s", re))
 	}
 	got := strings.Join(s, ", ")
-	e := "31:∞"
+	e := "5:9, 31:∞"
 	if got != e {
 		t.Fatalf("remove range: %q from %q gave %q, expected %q", b, a, got, e)
 	}
 }
 
+func TestRangeRemoveAllToLimits(t *testing.T) {
+	a := All()
+	for _, b := range []*Element{Range(10, intMax), Range(intMin, 10)} {
+		r := a.remove(b)
+		var s []string
+		for _, re := range r {
+			s = append(s, fmt.Sprintf("%s", re))
+		}
+		got := strings.Join(s, ", ")
+		e := "-∞:9"
+		if b.first == intMin {
+			e = "11:∞"
+		}
+		if got != e {
+			t.Fatalf("remove range: %q from %q gave %q, expected %q", b, a, got, e)
+		}
+	}
+}
+
+func TestRangeRemoveWithin(t *testing.T) {
+	tests := []struct {
+		a, b *Element
+	}{
+		{Range(3, 5), Range(0, 10)},
+		{Range(3, 5), NegInf(10)},
+		{Range(3, 5), PosInf(0)},
+		{PosInf(5), PosInf(0)},
+		{NegInf(5), NegInf(10)},
+	}
+
+	for _, tc := range tests {
+		var s []string
+		for _, re := range tc.a.remove(tc.b) {
+			s = append(s, fmt.Sprintf("%s", re))
+		}
+		if got := strings.Join(s, ", "); got != "" {
+			t.Fatalf("remove range: %q from %q gave %q, expected %q", tc.b, tc.a, got, "")
+		}
+	}
+}
+
 func TestRangeRemoveNegInfN(t *testing.T) {
 	a := NegInf(10)
 	b := Range(5, 25)
@@ -405,6 +492,15 @@ func TestIsAdjacent6b1(t *testing.T) {
 	}
 }
 
+func TestIsAdjacentLimits(t *testing.T) {
+	a := Range(intMax-1, intMax)
+	b := Range(intMin, intMin+1)
+	e := false
+	if a.isAdjacent(b) != e || b.isAdjacent(a) != e {
+		t.Fatalf("test: %q isAdjacent of %q returned %v, expected %v", a, b, a.isAdjacent(b), e)
+	}
+}
+
 func TestIsOverlapping1a(t *testing.T) {
 	a := All()
 	b := Range(10, 20)
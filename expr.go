@@ -0,0 +1,640 @@
+package intset
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op is an operator of a set expression.
+type Op int
+
+// The operators of set expressions, see ParseExpr.
+const (
+	OpUnion Op = iota
+	OpXor
+	OpIntersect
+	OpDifference
+	OpComplement
+)
+
+// String returns the operator as written in set expressions.
+func (o Op) String() string {
+	switch o {
+	case OpUnion:
+		return "|"
+	case OpXor:
+		return "^"
+	case OpIntersect:
+		return "&"
+	case OpDifference:
+		return "-"
+	case OpComplement:
+		return "!"
+	}
+
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+// precedence returns the binding strength of the operator. Higher
+// binds tighter.
+func (o Op) precedence() int {
+	return int(o) + 1
+}
+
+// Expr is a node of a parsed set expression.
+type Expr interface {
+	// Eval evaluates the expression, looking up variables in
+	// vars.
+	Eval(vars map[string]*IntSet) (*IntSet, error)
+
+	// Pos returns the byte offset of the expression in the
+	// parsed text.
+	Pos() int
+
+	// String returns the expression with as few parentheses as
+	// the precedence of the operators allows.
+	String() string
+}
+
+// SetLit is a literal set in an expression.
+type SetLit struct {
+	Set    *IntSet
+	Offset int
+}
+
+// VarRef is a reference to a variable in an expression, written
+// $name.
+type VarRef struct {
+	Name   string
+	Offset int
+}
+
+// UnaryExpr is the complement of an expression.
+type UnaryExpr struct {
+	Op     Op
+	X      Expr
+	Offset int
+}
+
+// BinaryExpr is a binary operation on two expressions.
+type BinaryExpr struct {
+	Op     Op
+	X, Y   Expr
+	Offset int
+}
+
+// SyntaxError is returned for expressions which can not be parsed.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+// Error returns the error message, in compliance with the error
+// interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("intset: syntax error at offset %d: %s", e.Offset, e.Msg)
+}
+
+// EvalExpr parses and evaluates the expression s, looking up
+// variables in vars.
+func EvalExpr(s string, vars map[string]*IntSet) (*IntSet, error) {
+	x, err := ParseExpr(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.Eval(vars)
+}
+
+// ParseExpr parses a set expression, e.g.
+//
+//	(1:100 | 500:inf) & !(42, 64:70) - $blocked
+//
+// The operands are elements written as for ParseElement, with ranges
+// written a:b as a-b is a difference, sets in braces as returned by
+// String, and variables written $name. A parenthesized list separated
+// by commas is the union of its items. The operators are, from the
+// tightest binding to the loosest:
+//
+//	!   complement, also written ¬
+//	-   difference, also written ∖
+//	&   intersection, also written ∩
+//	^   symmetric difference, also written ⊻
+//	|   union, also written ∪
+//
+// Binary operators of equal precedence group from the left.
+func ParseExpr(s string) (Expr, error) {
+	p := &exprParser{src: s}
+	p.next()
+	x, err := p.parseBinary(OpUnion.precedence())
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+
+	return x, nil
+}
+
+// Eval returns a copy of the literal set.
+func (x *SetLit) Eval(vars map[string]*IntSet) (*IntSet, error) {
+	return x.Set.Copy(), nil
+}
+
+// Pos returns the byte offset of the literal.
+func (x *SetLit) Pos() int {
+	return x.Offset
+}
+
+// String returns the literal as an element if it holds one, and in
+// braces otherwise.
+func (x *SetLit) String() string {
+	if len(x.Set.elements) == 1 {
+		return x.Set.elements[0].String()
+	}
+
+	return x.Set.String()
+}
+
+// Eval returns a copy of the set bound to the variable.
+func (x *VarRef) Eval(vars map[string]*IntSet) (*IntSet, error) {
+	s, ok := vars[x.Name]
+	if !ok || s == nil {
+		return nil, fmt.Errorf("intset: undefined variable $%s at offset %d", x.Name, x.Offset)
+	}
+
+	return s.Copy(), nil
+}
+
+// Pos returns the byte offset of the variable.
+func (x *VarRef) Pos() int {
+	return x.Offset
+}
+
+// String returns the variable as $name.
+func (x *VarRef) String() string {
+	return "$" + x.Name
+}
+
+// Eval returns the complement of the operand.
+func (x *UnaryExpr) Eval(vars map[string]*IntSet) (*IntSet, error) {
+	a, err := x.X.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Complement(), nil
+}
+
+// Pos returns the byte offset of the operator.
+func (x *UnaryExpr) Pos() int {
+	return x.Offset
+}
+
+// String returns the expression in a human readable form.
+func (x *UnaryExpr) String() string {
+	if _, ok := x.X.(*BinaryExpr); ok {
+		return fmt.Sprintf("%s(%s)", x.Op, x.X)
+	}
+
+	return fmt.Sprintf("%s%s", x.Op, x.X)
+}
+
+// Eval applies the operator to the operands.
+func (x *BinaryExpr) Eval(vars map[string]*IntSet) (*IntSet, error) {
+	a, err := x.X.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, err := x.Y.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyOp(x.Op, a, b), nil
+}
+
+// Pos returns the byte offset of the operator.
+func (x *BinaryExpr) Pos() int {
+	return x.Offset
+}
+
+// String returns the expression in a human readable form.
+func (x *BinaryExpr) String() string {
+	l, r := x.X.String(), x.Y.String()
+	if y, ok := x.X.(*BinaryExpr); ok && y.Op.precedence() < x.Op.precedence() {
+		l = "(" + l + ")"
+	}
+	if y, ok := x.Y.(*BinaryExpr); ok {
+		// the right operand needs parentheses unless it binds
+		// tighter, or both are the same associative operator
+		if y.Op.precedence() < x.Op.precedence() || y.Op == x.Op && x.Op == OpDifference {
+			r = "(" + r + ")"
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s", l, x.Op, r)
+}
+
+// applyOp applies the binary operator to a and b.
+func applyOp(op Op, a, b *IntSet) *IntSet {
+	switch op {
+	case OpUnion:
+		return a.Union(b)
+	case OpXor:
+		return a.Xor(b)
+	case OpIntersect:
+		return a.Intersect(b)
+	case OpDifference:
+		return a.Difference(b)
+	}
+
+	panic(fmt.Sprintf("intset: %s is not a binary operator", op))
+}
+
+// Simplify returns an equivalent expression where operations on
+// literals are evaluated, double complements are removed, and
+// operations with the empty set, all integers or identical operands
+// are reduced.
+func Simplify(x Expr) Expr {
+	switch x := x.(type) {
+	case *UnaryExpr:
+		y := Simplify(x.X)
+		if l, ok := y.(*SetLit); ok {
+			return &SetLit{Set: l.Set.Complement(), Offset: x.Offset}
+		} else if u, ok := y.(*UnaryExpr); ok {
+			return u.X
+		}
+		return &UnaryExpr{Op: x.Op, X: y, Offset: x.Offset}
+	case *BinaryExpr:
+		return simplifyBinary(x.Op, Simplify(x.X), Simplify(x.Y), x.Offset)
+	}
+
+	return x
+}
+
+// simplifyBinary returns the simplified form of the operation on the
+// simplified operands l and r.
+func simplifyBinary(op Op, l, r Expr, offset int) Expr {
+	ll, lok := l.(*SetLit)
+	rl, rok := r.(*SetLit)
+	if lok && rok {
+		return &SetLit{Set: applyOp(op, ll.Set, rl.Set), Offset: offset}
+	}
+
+	empty := func(ok bool, x *SetLit) bool {
+		return ok && len(x.Set.elements) == 0
+	}
+	all := func(ok bool, x *SetLit) bool {
+		return ok && len(x.Set.elements) == 1 && x.Set.elements[0].all && x.Set.elements[0].stride == 0
+	}
+	lit := func(s *IntSet) Expr {
+		return &SetLit{Set: s, Offset: offset}
+	}
+	same := l.String() == r.String()
+
+	switch op {
+	case OpUnion:
+		if empty(lok, ll) {
+			return r
+		} else if empty(rok, rl) || same {
+			return l
+		} else if all(lok, ll) || all(rok, rl) {
+			return lit(New(All()))
+		}
+	case OpIntersect:
+		if empty(lok, ll) || empty(rok, rl) {
+			return lit(New())
+		} else if all(lok, ll) {
+			return r
+		} else if all(rok, rl) || same {
+			return l
+		}
+	case OpDifference:
+		if empty(lok, ll) || all(rok, rl) || same {
+			return lit(New())
+		} else if empty(rok, rl) {
+			return l
+		} else if all(lok, ll) {
+			return &UnaryExpr{Op: OpComplement, X: r, Offset: offset}
+		}
+	case OpXor:
+		if same {
+			return lit(New())
+		} else if empty(lok, ll) {
+			return r
+		} else if empty(rok, rl) {
+			return l
+		}
+	}
+
+	return &BinaryExpr{Op: op, X: l, Y: r, Offset: offset}
+}
+
+// tokKind is the kind of a token of a set expression.
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNum
+	tokIdent
+	tokVar
+	tokBrace
+	tokPunct
+)
+
+// token is a token of a set expression.
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// String returns the token as used in error messages.
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+
+	return fmt.Sprintf("%q", t.text)
+}
+
+// exprParser is a recursive descent parser of set expressions.
+type exprParser struct {
+	src string
+	off int
+	tok token
+	err error
+}
+
+// errorf returns a syntax error at the current token.
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	return &SyntaxError{Offset: p.tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next scans the next token.
+func (p *exprParser) next() {
+	for p.off < len(p.src) {
+		r, n := utf8.DecodeRuneInString(p.src[p.off:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.off += n
+	}
+
+	start := p.off
+	if p.off >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	r, n := utf8.DecodeRuneInString(p.src[p.off:])
+	switch {
+	case r >= '0' && r <= '9':
+		p.off = p.scan(p.off, func(r rune) bool { return r >= '0' && r <= '9' })
+		p.tok = token{kind: tokNum, text: p.src[start:p.off], pos: start}
+	case unicode.IsLetter(r):
+		p.off = p.scan(p.off, unicode.IsLetter)
+		p.tok = token{kind: tokIdent, text: p.src[start:p.off], pos: start}
+	case r == '$':
+		p.off = p.scan(p.off+n, isVarRune)
+		p.tok = token{kind: tokVar, text: p.src[start+n : p.off], pos: start}
+		if p.tok.text == "" {
+			p.err = &SyntaxError{Offset: start, Msg: "missing variable name"}
+		}
+	case r == '{':
+		end := strings.IndexByte(p.src[p.off:], '}')
+		if end < 0 {
+			p.err = &SyntaxError{Offset: start, Msg: "missing closing brace"}
+			p.off = len(p.src)
+			p.tok = token{kind: tokEOF, pos: start}
+			return
+		}
+		p.off += end + 1
+		p.tok = token{kind: tokBrace, text: p.src[start:p.off], pos: start}
+	default:
+		p.off += n
+		text := string(r)
+		switch r {
+		case '∪':
+			text = "|"
+		case '∩':
+			text = "&"
+		case '∖':
+			text = "-"
+		case '⊻':
+			text = "^"
+		case '¬':
+			text = "!"
+		}
+		p.tok = token{kind: tokPunct, text: text, pos: start}
+		if !strings.Contains("()|&-^!,:+∞", text) {
+			p.err = &SyntaxError{Offset: start, Msg: fmt.Sprintf("unexpected %q", text)}
+		}
+	}
+}
+
+// scan returns the offset of the first rune at or after off not
+// accepted by f.
+func (p *exprParser) scan(off int, f func(rune) bool) int {
+	for off < len(p.src) {
+		r, n := utf8.DecodeRuneInString(p.src[off:])
+		if !f(r) {
+			break
+		}
+		off += n
+	}
+
+	return off
+}
+
+// isVarRune returns true if r can be part of a variable name.
+func isVarRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// binaryOp returns the binary operator of the current token.
+func (p *exprParser) binaryOp() (Op, bool) {
+	if p.tok.kind != tokPunct {
+		return 0, false
+	}
+
+	switch p.tok.text {
+	case "|":
+		return OpUnion, true
+	case "^":
+		return OpXor, true
+	case "&":
+		return OpIntersect, true
+	case "-":
+		return OpDifference, true
+	}
+
+	return 0, false
+}
+
+// parseBinary parses operations binding at least as tight as prec.
+func (p *exprParser) parseBinary(prec int) (Expr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.err != nil {
+			return nil, p.err
+		}
+		op, ok := p.binaryOp()
+		if !ok || op.precedence() < prec {
+			return x, nil
+		}
+		pos := p.tok.pos
+		p.next()
+		y, err := p.parseBinary(op.precedence() + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: op, X: x, Y: y, Offset: pos}
+	}
+}
+
+// parseUnary parses an operand, possibly complemented.
+func (p *exprParser) parseUnary() (Expr, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	t := p.tok
+	switch {
+	case t.kind == tokPunct && t.text == "!":
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: OpComplement, X: x, Offset: t.pos}, nil
+	case t.kind == tokPunct && t.text == "(":
+		p.next()
+		return p.parseList(t.pos)
+	case t.kind == tokVar:
+		p.next()
+		return &VarRef{Name: t.text, Offset: t.pos}, nil
+	case t.kind == tokBrace:
+		s, err := Parse(t.text)
+		if err != nil {
+			return nil, &SyntaxError{Offset: t.pos, Msg: strings.TrimPrefix(err.Error(), "intset: ")}
+		}
+		p.next()
+		return &SetLit{Set: s, Offset: t.pos}, nil
+	case t.kind == tokNum, t.kind == tokIdent, t.kind == tokPunct && (t.text == "-" || t.text == "∞"):
+		return p.parseElement()
+	}
+
+	return nil, p.errorf("unexpected %s", t)
+}
+
+// parseList parses the items of a parenthesized list after the opening
+// parenthesis at pos. The list is the union of its items, and lists
+// of literals are joined into one literal.
+func (p *exprParser) parseList(pos int) (Expr, error) {
+	var x Expr
+	for {
+		y, err := p.parseBinary(OpUnion.precedence())
+		if err != nil {
+			return nil, err
+		}
+		if x == nil {
+			x = y
+		} else if l, ok := x.(*SetLit); ok {
+			if r, ok := y.(*SetLit); ok {
+				x = &SetLit{Set: l.Set.Union(r.Set), Offset: pos}
+			} else {
+				x = &BinaryExpr{Op: OpUnion, X: x, Y: y, Offset: y.Pos()}
+			}
+		} else {
+			x = &BinaryExpr{Op: OpUnion, X: x, Y: y, Offset: y.Pos()}
+		}
+
+		if p.tok.kind == tokPunct && p.tok.text == "," {
+			p.next()
+			continue
+		} else if p.tok.kind == tokPunct && p.tok.text == ")" {
+			p.next()
+			return x, p.err
+		}
+		return nil, p.errorf("expected %q or %q, found %s", ",", ")", p.tok)
+	}
+}
+
+// parseElement parses an element literal, which is handed to
+// ParseElement.
+func (p *exprParser) parseElement() (Expr, error) {
+	pos := p.tok.pos
+	var b strings.Builder
+
+	bound := func() error {
+		if p.tok.kind == tokPunct && p.tok.text == "-" {
+			b.WriteString("-")
+			p.next()
+		}
+		switch {
+		case p.tok.kind == tokNum:
+		case p.tok.kind == tokPunct && p.tok.text == "∞":
+		case p.tok.kind == tokIdent && strings.EqualFold(p.tok.text, "inf"):
+		default:
+			return p.errorf("expected integer or %c, found %s", 0x221e, p.tok)
+		}
+		b.WriteString(p.tok.text)
+		p.next()
+		return nil
+	}
+
+	if err := bound(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokIdent && (p.tok.text == "Z" || p.tok.text == "ℤ") {
+		// sℤ+r
+		b.WriteString(p.tok.text)
+		p.next()
+		if p.tok.kind == tokPunct && p.tok.text == "+" {
+			b.WriteString("+")
+			p.next()
+			if p.tok.kind != tokNum {
+				return nil, p.errorf("expected residue, found %s", p.tok)
+			}
+			b.WriteString(p.tok.text)
+			p.next()
+		}
+	} else {
+		for i := 0; i < 2 && p.tok.kind == tokPunct && p.tok.text == ":"; i++ {
+			b.WriteString(":")
+			p.next()
+			if i == 1 {
+				if p.tok.kind != tokNum {
+					return nil, p.errorf("expected stride, found %s", p.tok)
+				}
+				b.WriteString(p.tok.text)
+				p.next()
+			} else if err := bound(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	e, err := ParseElement(b.String())
+	if err != nil {
+		return nil, &SyntaxError{Offset: pos, Msg: strings.TrimPrefix(err.Error(), "intset: ")}
+	}
+
+	return &SetLit{Set: New(e), Offset: pos}, nil
+}
@@ -0,0 +1,125 @@
+package intset
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEvalExpr(t *testing.T) {
+	vars := map[string]*IntSet{
+		"blocked": New(Range(90, 95), Int(600)),
+		"even":    New(StepAll(0, 2)),
+	}
+
+	tests := map[string]string{
+		"(1:100 | 500:inf) & !(42, 64:70) - $blocked": "{1:41, 43:63, 71:89, 96:100, 500:599, 601:∞}",
+		"1:10 | 5:20 & 8:9":                           "{1:10}",
+		"1:10 - 3:4 - 6":                              "{1:2, 5, 7:10}",
+		"1:10 - (3:4 - 4)":                            "{1:2, 4:10}",
+		"1:10 ^ 5:15":                                 "{1:4, 11:15}",
+		"-5:-1 ∪ -inf:-10 ∩ -∞:-20":                   "{-∞:-20, -5:-1}",
+		"!!$blocked":                                  "{90:95, 600}",
+		"¬(1:∞) ∖ -3":                                 "{-∞:-4, -2:0}",
+		"$even & 1:10 - 3ℤ":                           "{2, 4:10:6, 8}",
+		"{1:3, 7} | 0:20:5":                           "{0:20:5, 1:3, 7}",
+	}
+
+	for s, e := range tests {
+		a, err := EvalExpr(s, vars)
+		if err != nil {
+			t.Fatalf("eval %q failed: %v", s, err)
+		}
+		if fmt.Sprintf("%s", a) != e {
+			t.Fatalf("eval %q failed: got %s, expected %s", s, a, e)
+		}
+	}
+
+	if _, err := EvalExpr("1 | $missing", vars); err == nil {
+		t.Fatalf("eval of undefined variable failed: got no error")
+	}
+
+	x, _ := ParseExpr("$blocked")
+	a, _ := x.Eval(vars)
+	a.AddInts(1)
+	if fmt.Sprintf("%s", vars["blocked"]) != "{90:95, 600}" {
+		t.Fatalf("eval failed: variable modified to %s", vars["blocked"])
+	}
+}
+
+func TestExprString(t *testing.T) {
+	tests := map[string]string{
+		"(1:100 | 500:inf) & !(42, 64:70) - $blocked": "(1:100 | 500:∞) & !{42, 64:70} - $blocked",
+		"(($a | $b) | $c)":      "$a | $b | $c",
+		"$a | ($b | $c)":        "$a | $b | $c",
+		"$a - ($b - $c)":        "$a - ($b - $c)",
+		"($a - $b) - $c":        "$a - $b - $c",
+		"($a & $b) ^ ($c - $d)": "$a & $b ^ $c - $d",
+		"!($a | $b) & !$c":      "!($a | $b) & !$c",
+		"-5 - -∞:-3 - 3ℤ+1":     "-5 - -∞:-3 - 3ℤ+1",
+	}
+
+	for s, e := range tests {
+		x, err := ParseExpr(s)
+		if err != nil {
+			t.Fatalf("parse %q failed: %v", s, err)
+		}
+		if x.String() != e {
+			t.Fatalf("string of %q failed: got %s, expected %s", s, x, e)
+		}
+		if y, err := ParseExpr(x.String()); err != nil || y.String() != e {
+			t.Fatalf("parse of string %q failed: got %v, %v", e, y, err)
+		}
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	tests := map[string]string{
+		"1:10 | 20":             "{1:10, 20}",
+		"$a | 1:3 - 1:3":        "$a",
+		"$a & -∞:∞":             "$a",
+		"-∞:∞ - $a":             "!$a",
+		"!!($a ^ $a | $b)":      "$b",
+		"($a | $b) & ($a | $b)": "$a | $b",
+		"$a - (1 - 1:5) & $b":   "$a & $b",
+		"$a & {∅} | $c - !!$c":  "{∅}",
+	}
+
+	for s, e := range tests {
+		x, err := ParseExpr(s)
+		if err != nil {
+			t.Fatalf("parse %q failed: %v", s, err)
+		}
+		if y := Simplify(x); y.String() != e {
+			t.Fatalf("simplify %q failed: got %s, expected %s", s, y, e)
+		}
+	}
+}
+
+func TestParseExprInvalid(t *testing.T) {
+	tests := map[string]int{
+		"":             0,
+		"1:10 |":       6,
+		"1:10 | | 3":   7,
+		"(1, 2":        5,
+		"1 & $":        4,
+		"1 # 2":        2,
+		"{1, 2":        0,
+		"3 | {1, x}":   4,
+		"5:-∞":         0,
+		"$a 1":         3,
+		"1:2:":         4,
+		"1:5 & !(2 3)": 10,
+	}
+
+	for s, e := range tests {
+		_, err := ParseExpr(s)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Fatalf("parse %q failed: got %v, expected syntax error", s, err)
+		}
+		if se.Offset != e {
+			t.Fatalf("parse %q failed: got error at %d (%v), expected at %d", s, se.Offset, err, e)
+		}
+	}
+}
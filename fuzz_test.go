@@ -0,0 +1,71 @@
+package intset
+
+import (
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{
+		"{-∞:-5, 7, 10:20:2, 30:∞}",
+		"1-5,7",
+		"1\n2\n3",
+		"{∅}",
+		"3ℤ+1, -∞:10:4",
+		"-inf:inf",
+		"5:1",
+		"-9223372036854775808:9223372036854775807:3",
+	} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		a, err := Parse(s)
+		if err != nil {
+			return
+		}
+		checkCanonical(t, "parse", a)
+
+		b, err := Parse(a.String())
+		if err != nil {
+			t.Fatalf("parse of string %s failed: %v", a, err)
+		}
+		if !a.Equal(b) {
+			t.Fatalf("parse of string %s failed: got %s", a, b)
+		}
+	})
+}
+
+func FuzzAlgebra(f *testing.F) {
+	f.Add([]byte{1, 0, 5, 2, 20, 30}, []byte{3, 8, 2, 6, 200, 0}, uint8(0))
+	f.Add([]byte{5, 4, 3}, []byte{4, 0, 1, 0, 10, 0}, uint8(1))
+	f.Add([]byte{7, 0, 0, 2, 40, 7}, []byte{5, 1, 2, 5, 2, 3}, uint8(2))
+
+	f.Fuzz(func(t *testing.T, x, y []byte, sel uint8) {
+		base := bases[int(sel)%len(bases)]
+		a, b := decodeSet(x, base), decodeSet(y, base)
+		ma, mb := model(a, base), model(b, base)
+
+		u, i, d := a.Union(b), a.Intersect(b), a.Difference(b)
+		for name, s := range map[string]*IntSet{"union": u, "intersect": i, "difference": d, "complement": a.Complement()} {
+			checkCanonical(t, name, s)
+		}
+
+		mu, mi, md := model(u, base), model(i, base), model(d, base)
+		for n := range mu {
+			if !ma[n] && !mb[n] {
+				t.Fatalf("union failed: %s, %s gave %s, wrong at %d", a, b, u, n)
+			}
+		}
+		for n := range ma {
+			if !mu[n] || mi[n] != mb[n] || md[n] == mb[n] {
+				t.Fatalf("algebra failed: %s, %s gave %s, %s, %s, wrong at %d", a, b, u, i, d, n)
+			}
+		}
+		if !a.Xor(b).Equal(d.Union(b.Difference(a))) {
+			t.Fatalf("xor failed: %s, %s gave %s", a, b, a.Xor(b))
+		}
+		if !u.Complement().Equal(a.Complement().Intersect(b.Complement())) {
+			t.Fatalf("de morgan failed: %s, %s", a, b)
+		}
+	})
+}
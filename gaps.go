@@ -0,0 +1,146 @@
+package intset
+
+import (
+	"iter"
+)
+
+// Gaps returns the integers from lo to hi which are not part of the
+// set, i.e. the complement of the set restricted to the window.
+func (a *IntSet) Gaps(lo, hi int) *IntSet {
+	return a.Complement().Clamp(lo, hi)
+}
+
+// Holes returns an iterator over the finite gaps between the ranges
+// of the set in ascending order. The infinite ends of the complement
+// are not holes. Finite progressions have holes between their
+// integers, which are found without expanding them, while infinite
+// progressions are treated as ranges with holes of their own.
+func (a *IntSet) Holes() iter.Seq[*Element] {
+	return func(yield func(*Element) bool) {
+		if len(a.elements) == 0 {
+			return
+		}
+		if !a.strided() {
+			for i := 1; i < len(a.elements); i++ {
+				_, hi, _, _ := a.elements[i-1].bounds()
+				lo, _, _, _ := a.elements[i].bounds()
+				if !yield(Range(hi+1, lo-1)) {
+					return
+				}
+			}
+			return
+		}
+
+		n, neg := 0, false
+		for i, e := range a.elements {
+			if e.stride > 1 && e.inf() {
+				for _, e := range a.Complement().elements {
+					if !e.inf() && !yield(e) {
+						return
+					}
+				}
+				return
+			}
+			lo, hi, eneg, _ := e.bounds()
+			if eneg {
+				n, neg = hi, true
+			} else if !neg && (i == 0 || lo < n) {
+				n = lo
+			}
+		}
+
+		for {
+			end, pos := a.runEnd(n)
+			if pos {
+				return
+			}
+			next, ok := a.NextAfter(end)
+			if !ok || !yield(Range(end+1, next-1)) {
+				return
+			}
+			n = next
+		}
+	}
+}
+
+// runEnd returns the last integer of the run of consecutive integers
+// of the set starting at n, and true if the run is infinite. The set
+// must hold n and no infinite progressions.
+func (a *IntSet) runEnd(n int) (int, bool) {
+	for extended := true; extended && n < intMax; {
+		extended = false
+		for _, e := range a.elements {
+			if !e.has(n + 1) {
+				continue
+			}
+			_, hi, _, pos := e.bounds()
+			if e.stride > 1 {
+				n++
+			} else if pos {
+				return 0, true
+			} else {
+				n = hi
+			}
+			extended = true
+			if n == intMax {
+				break
+			}
+		}
+	}
+
+	return n, false
+}
+
+// LargestGap returns the largest hole of the set, and false if the set
+// has no holes. The first of equally large holes is returned.
+func (a *IntSet) LargestGap() (*Element, bool) {
+	var largest *Element
+	var size uint
+	for h := range a.Holes() {
+		if c, _ := h.Cardinality(); largest == nil || c > size {
+			largest, size = h, c
+		}
+	}
+
+	return largest, largest != nil
+}
+
+// Coverage returns the share of the integers from lo to hi which are
+// part of the set, as a number from 0 to 1.
+func (a *IntSet) Coverage(lo, hi int) float64 {
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+
+	c, inf := a.Clamp(lo, hi).Cardinality()
+	if inf {
+		// every integer of the platform is covered
+		return 1
+	}
+	w := uint(hi) - uint(lo) + 1
+	if w == 0 {
+		// the window spans every integer of the platform
+		return float64(c) / (float64(^uint(0)) + 1)
+	}
+
+	return float64(c) / float64(w)
+}
+
+// Fragmentation returns the number of ranges of the set divided by
+// its cardinality. It is 1 when the set consists of single integers
+// only, and approaches 0 as the set consists of fewer and larger
+// ranges. Empty and infinite sets have a fragmentation of 0.
+func (a *IntSet) Fragmentation() float64 {
+	c, inf := a.Cardinality()
+	if inf || c == 0 {
+		return 0
+	}
+
+	// the first integers of the runs are those not following another
+	// integer of the set, where intMax is left out as nothing follows
+	// it
+	shifted, _ := a.Difference(New(Int(intMax))).Shift(1)
+	runs, _ := a.Difference(shifted).Cardinality()
+
+	return float64(runs) / float64(c)
+}
@@ -0,0 +1,152 @@
+package intset
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestGaps(t *testing.T) {
+	a := New(Range(0, 10), Range(15, 20), Int(25), PosInf(40))
+	e := "{-5:-1, 11:14, 21:24, 26:30}"
+	if fmt.Sprintf("%s", a.Gaps(-5, 30)) != e {
+		t.Fatalf("gaps failed: got %s, expected %s", a.Gaps(-5, 30), e)
+	}
+}
+
+func TestHoles(t *testing.T) {
+	a := New(NegInf(-10), Range(0, 10), Range(15, 20), Int(25), PosInf(40))
+	var got []string
+	for h := range a.Holes() {
+		got = append(got, h.String())
+	}
+	e := "-9:-1, 11:14, 21:24, 26:39"
+	if strings.Join(got, ", ") != e {
+		t.Fatalf("holes failed: got %s, expected %s", strings.Join(got, ", "), e)
+	}
+
+	got = nil
+	for h := range New(Step(0, 8, 4), Int(6)).Holes() {
+		got = append(got, h.String())
+	}
+	e = "1:3, 5, 7"
+	if strings.Join(got, ", ") != e {
+		t.Fatalf("holes failed: got %s, expected %s", strings.Join(got, ", "), e)
+	}
+
+	got = nil
+	for h := range New(Step(0, 20, 4), Range(5, 7)).Holes() {
+		got = append(got, h.String())
+	}
+	e = "1:3, 9:11, 13:15, 17:19"
+	if strings.Join(got, ", ") != e {
+		t.Fatalf("holes failed: got %s, expected %s", strings.Join(got, ", "), e)
+	}
+
+	// progressions are not expanded
+	got = nil
+	for h := range New(Step(0, intMax, 2)).Holes() {
+		if got = append(got, h.String()); len(got) == 3 {
+			break
+		}
+	}
+	e = "1, 3, 5"
+	if strings.Join(got, ", ") != e {
+		t.Fatalf("holes failed: got %s, expected %s", strings.Join(got, ", "), e)
+	}
+}
+
+func TestPropertyHoles(t *testing.T) {
+	r := rand.New(rand.NewSource(10))
+	for it := 0; it < 1000; it++ {
+		a := randSet(r, bases[it%len(bases)])
+
+		var holes []*Element
+		for h := range a.Holes() {
+			holes = append(holes, h)
+		}
+
+		// the holes as found between the ranges of the expanded set,
+		// while the holes of infinite progressions depend on the form
+		// of the complement
+		b := a.expand()
+		if b.strided() {
+			for _, h := range holes {
+				if h.inf() || len(New(h).Intersect(a).elements) > 0 {
+					t.Fatalf("holes of %s failed: got %v", a, holes)
+				}
+			}
+		} else {
+			var expected []string
+			for i := 1; i < len(b.elements); i++ {
+				_, hi, _, _ := b.elements[i-1].bounds()
+				lo, _, _, _ := b.elements[i].bounds()
+				expected = append(expected, Range(hi+1, lo-1).String())
+			}
+			if got := fmt.Sprint(holes); got != fmt.Sprint(expected) {
+				t.Fatalf("holes of %s failed: got %s, expected %s", a, got, expected)
+			}
+		}
+
+		f := 0.0
+		if c, inf := b.Cardinality(); !inf && c > 0 {
+			f = float64(len(b.elements)) / float64(c)
+		}
+		if got := a.Fragmentation(); got != f {
+			t.Fatalf("fragmentation of %s failed: got %f, expected %f", a, got, f)
+		}
+	}
+}
+
+func TestLargestGap(t *testing.T) {
+	a := New(Range(0, 10), Range(15, 20), Int(25), Range(30, 33))
+	g, ok := a.LargestGap()
+	if !ok {
+		t.Fatalf("largest gap failed: no gap found in %s", a)
+	}
+	if lo, _ := g.Min(); lo != 11 {
+		t.Fatalf("largest gap failed: got %s, expected 11:14", g)
+	}
+	if c, _ := g.Cardinality(); c != 4 {
+		t.Fatalf("largest gap failed: got cardinality %d, expected 4", c)
+	}
+
+	if _, ok := New(PosInf(1)).LargestGap(); ok {
+		t.Fatalf("largest gap failed: expected no gap")
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	a := New(Range(0, 9), Range(20, 29))
+	if c := a.Coverage(0, 39); c != 0.5 {
+		t.Fatalf("coverage failed: got %f, expected 0.5", c)
+	}
+	if c := a.Coverage(10, 19); c != 0 {
+		t.Fatalf("coverage failed: got %f, expected 0", c)
+	}
+	if c := New(All()).Coverage(intMin, intMax); c != 1 {
+		t.Fatalf("coverage failed: got %f, expected 1", c)
+	}
+}
+
+func TestFragmentation(t *testing.T) {
+	a := New(Int(1), Int(3), Int(5), Int(7))
+	if f := a.Fragmentation(); f != 1 {
+		t.Fatalf("fragmentation failed: got %f, expected 1", f)
+	}
+	a = New(Range(1, 10), Range(21, 30))
+	if f := a.Fragmentation(); f != 0.1 {
+		t.Fatalf("fragmentation failed: got %f, expected 0.1", f)
+	}
+	if f := New(PosInf(0)).Fragmentation(); f != 0 {
+		t.Fatalf("fragmentation failed: got %f, expected 0", f)
+	}
+	a = New(Step(0, 20, 4), Range(5, 7))
+	if f := a.Fragmentation(); f != 5.0/9 {
+		t.Fatalf("fragmentation failed: got %f, expected %f", f, 5.0/9)
+	}
+	if f := New(Step(0, intMax, 2), Int(-1)).Fragmentation(); f != 1 {
+		t.Fatalf("fragmentation failed: got %f, expected 1", f)
+	}
+}
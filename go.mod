@@ -1,3 +1,3 @@
 module github.com/stianwa/intset
 
-go 1.14
+go 1.23
@@ -0,0 +1,169 @@
+package intset
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// hashPrime is the Mersenne prime 2^61-1, modulo which Hash sums.
+const hashPrime = 1<<61 - 1
+
+// hashBase is the base whose powers are summed by Hash.
+const hashBase = 0x2545f4914f6cdd1d % hashPrime
+
+// Hash returns a hash of the set, consistent with Equal: sets which
+// are Equal have the same hash. It is the sum of b^(x-MinInt) modulo
+// 2^61-1 over the integers x of the set within the limits of the
+// platform, for a fixed base b, found as geometric series without
+// expanding the elements. It is stable across processes and platforms
+// of the same int size.
+func (a *IntSet) Hash() uint64 {
+	var h uint64
+	for _, e := range a.elements {
+		lo, hi, neg, pos := e.bounds()
+		s, r := e.step(), e.residue()
+		var ok bool
+		if neg {
+			if lo, ok = alignUp(intMin, r, s); !ok {
+				continue
+			}
+		}
+		if pos {
+			if hi, ok = alignDown(intMax, r, s); !ok {
+				continue
+			}
+		}
+		if lo > hi {
+			continue
+		}
+
+		first := hashPow(hashBase, uint64(uint(lo)-(uint(intMax)+1)))
+		q := hashPow(hashBase, uint64(s))
+		c := uint64((uint(hi) - uint(lo)) / uint(s))
+		if c == uint64(^uint(0)) {
+			// every integer of the platform, as two halves, since
+			// the count overflows
+			half := c/2 + 1
+			sum := hashGeometric(q, half)
+			sum = hashAdd(sum, hashMul(hashPow(q, half), sum))
+			h = hashAdd(h, hashMul(first, sum))
+			continue
+		}
+		h = hashAdd(h, hashMul(first, hashGeometric(q, c+1)))
+	}
+
+	return h
+}
+
+// hashAdd returns a+b modulo hashPrime.
+func hashAdd(a, b uint64) uint64 {
+	if a += b; a >= hashPrime {
+		a -= hashPrime
+	}
+
+	return a
+}
+
+// hashMul returns a*b modulo hashPrime.
+func hashMul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	n := hi<<3 | lo>>61 + lo&hashPrime
+	n = n>>61 + n&hashPrime
+	if n >= hashPrime {
+		n -= hashPrime
+	}
+
+	return n
+}
+
+// hashPow returns b^n modulo hashPrime.
+func hashPow(b, n uint64) uint64 {
+	p := uint64(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			p = hashMul(p, b)
+		}
+		b = hashMul(b, b)
+	}
+
+	return p
+}
+
+// hashGeometric returns the sum of q^k for k from 0 to n-1 modulo
+// hashPrime, built from the most significant bit of n down.
+func hashGeometric(q, n uint64) uint64 {
+	var sum uint64
+	qn := uint64(1)
+	for i := bits.Len64(n) - 1; i >= 0; i-- {
+		// from m to 2m integers
+		sum = hashMul(sum, hashAdd(1, qn))
+		qn = hashMul(qn, qn)
+		if n>>uint(i)&1 == 1 {
+			// from m to m+1 integers
+			sum = hashAdd(hashMul(sum, q), 1)
+			qn = hashMul(qn, q)
+		}
+	}
+
+	return sum
+}
+
+// Compare returns -1 if a is ordered before b, 1 if a is ordered after
+// b, and 0 if they are Equal, for use with slices.SortFunc. Sets are
+// ordered lexicographically by their integers in ascending order: at
+// the first integer held by only one of the sets, that set comes
+// first, unless the other set holds no larger integers and so is a
+// prefix of it. The empty set is thus first among sets with a
+// smallest integer. Sets infinite in the negative direction come
+// before all others, ordered by the patterns of their negative ends,
+// where the pattern holding the first integer from 0 held by only one
+// of them comes first.
+func Compare(a, b *IntSet) int {
+	ea, eb := endSet(a), endSet(b)
+	switch {
+	case len(ea.elements) == 0 && len(eb.elements) > 0:
+		return 1
+	case len(eb.elements) == 0 && len(ea.elements) > 0:
+		return -1
+	case !ea.Equal(eb):
+		if r, _ := ea.Xor(eb).NextAfter(-1); ea.HasInt(r) {
+			return -1
+		}
+		return 1
+	}
+
+	d := a.Xor(b)
+	if len(d.elements) == 0 {
+		if a.Equal(b) {
+			return 0
+		}
+		// ranges to ∞ and ranges to the limit of the platform
+		return strings.Compare(a.Key(), b.Key())
+	}
+
+	x, _ := d.elements[0].Min()
+	if a.HasInt(x) {
+		if _, ok := b.NextAfter(x); ok {
+			return -1
+		}
+		return 1
+	}
+	if _, ok := a.NextAfter(x); ok {
+		return 1
+	}
+
+	return -1
+}
+
+// endSet returns the periodic pattern of the negative end of the set,
+// as a set of progressions infinite in both directions.
+func endSet(a *IntSet) *IntSet {
+	n := New()
+	for _, e := range a.elements {
+		if e.neginf || e.all {
+			n.insertElement(newStep(0, 0, true, true, e.step(), e.residue()))
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,232 @@
+package intset
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		a, b *IntSet
+	}{
+		{New(StepAll(0, 4), StepAll(2, 4)), New(StepAll(0, 2))},
+		{New(StepAll(0, 2), Step(-9, 9, 3)), New(StepAll(0, 2), Step(-9, 9, 6), Int(-6), Int(0), Int(6))},
+		{New(StepNegInf(0, 3), StepPosInf(3, 3), Int(-1)), New(StepAll(0, 3), Int(-1))},
+		{New(StepAll(1, 2), StepNegInf(0, 2)), New(NegInf(0), StepPosInf(1, 2))},
+		{New(Step(0, 10, 5), Range(20, 30)), New(Int(0), Int(5), Int(10), Range(20, 30))},
+		{New(Step(0, 600000, 6), Step(3, 600003, 6)), New(Step(0, 600003, 3))},
+		{New(Step(0, 1<<30, 2), Step(1, 1<<30, 4)), New(Step(0, 1<<30, 4), Step(1, 1<<30, 4), Step(2, 1<<30, 4))},
+		{New(StepAll(0, 140002), StepAll(70001, 140002)), New(StepAll(0, 70001))},
+		{New(StepPosInf(0, 2), Range(-1000, 1000)), New(Range(-1000, 1000), StepPosInf(1002, 4), StepPosInf(1004, 4))},
+	}
+
+	for _, tc := range tests {
+		if !tc.a.Equal(tc.b) {
+			t.Fatalf("equal of %s and %s failed: got false", tc.a, tc.b)
+		}
+		if tc.a.Key() != tc.b.Key() {
+			t.Fatalf("key of %s and %s failed: got %s and %s", tc.a, tc.b, tc.a.Key(), tc.b.Key())
+		}
+	}
+
+	if a, b := New(StepAll(0, 2)), New(StepAll(0, 4)); a.Key() == b.Key() {
+		t.Fatalf("key of %s and %s failed: got %s for both", a, b, a.Key())
+	}
+
+	m := map[string]int{}
+	for _, tc := range tests {
+		m[tc.a.Key()]++
+		m[tc.b.Key()]++
+	}
+	if len(m) != len(tests) {
+		t.Fatalf("map keys failed: got %d keys, expected %d", len(m), len(tests))
+	}
+}
+
+func TestHash(t *testing.T) {
+	// the sum of the powers of the integers, one by one
+	sum := func(ns ...int) uint64 {
+		var h uint64
+		for _, n := range ns {
+			h = hashAdd(h, hashPow(hashBase, uint64(uint(n)-(uint(intMax)+1))))
+		}
+		return h
+	}
+
+	tests := []struct {
+		a        *IntSet
+		expected uint64
+	}{
+		{New(), 0},
+		{New(Int(0)), sum(0)},
+		{New(Int(intMin), Int(intMax)), sum(intMin, intMax)},
+		{New(Range(-3, 2), Step(10, 22, 4)), sum(-3, -2, -1, 0, 1, 2, 10, 14, 18, 22)},
+		{New(StepPosInf(intMax-10, 3)), sum(intMax-10, intMax-7, intMax-4, intMax-1)},
+		{New(StepNegInf(intMin+5, 2)), sum(intMin+1, intMin+3, intMin+5)},
+		{New(NegInf(intMin + 2)), sum(intMin, intMin+1, intMin+2)},
+	}
+	for _, tc := range tests {
+		if got := tc.a.Hash(); got != tc.expected {
+			t.Fatalf("hash of %s failed: got %d, expected %d", tc.a, got, tc.expected)
+		}
+	}
+
+	// sets which are Equal, built differently
+	equal := []struct {
+		a, b *IntSet
+	}{
+		{New(StepAll(0, 140002), StepAll(70001, 140002)), New(StepAll(0, 70001))},
+		{New(Step(0, intMax/2, 2)), New(Step(0, intMax/2, 4), Step(2, intMax/2, 4))},
+		{New(All()), New(NegInf(-1), PosInf(0))},
+		{New(All()), New(StepAll(0, 3), StepAll(1, 3), StepAll(2, 3))},
+	}
+	for _, tc := range equal {
+		if !tc.a.Equal(tc.b) {
+			t.Fatalf("equal of %s and %s failed: got false", tc.a, tc.b)
+		}
+		if tc.a.Hash() != tc.b.Hash() || tc.a.Key() != tc.b.Key() {
+			t.Fatalf("hash of %s and %s failed: got %d and %d, keys %s and %s", tc.a, tc.b, tc.a.Hash(), tc.b.Hash(), tc.a.Key(), tc.b.Key())
+		}
+	}
+
+	// the same integers of the platform, but different infinite ends
+	if a, b := New(StepPosInf(0, 2)), New(Step(0, intMax-1, 2)); a.Equal(b) || a.Key() == b.Key() || a.Hash() != b.Hash() {
+		t.Fatalf("hash of %s and %s failed: got equal %t, keys %s and %s, hashes %d and %d", a, b, a.Equal(b), a.Key(), b.Key(), a.Hash(), b.Hash())
+	}
+
+	if a, b := New(StepAll(0, 2)), New(StepAll(0, 4)); a.Hash() == b.Hash() {
+		t.Fatalf("hash of %s and %s failed: got %d for both", a, b, a.Hash())
+	}
+
+	// the same sets built in different ways, near the platform limits
+	r := rand.New(rand.NewSource(10))
+	for _, base := range bases {
+		for it := 0; it < 100; it++ {
+			a, b := randSet(r, base), randSet(r, base)
+			sets := []*IntSet{
+				a.Union(b),
+				a.Difference(b).Union(a.Intersect(b)).Union(b),
+				a.Complement().Intersect(b.Complement()).Complement(),
+			}
+			for _, x := range sets[1:] {
+				// the infinite ends may differ near the limits
+				if !sameIntegers(x, sets[0]) || x.Hash() != sets[0].Hash() {
+					t.Fatalf("hash of %s and %s failed: got %d and %d", sets[0], x, sets[0].Hash(), x.Hash())
+				}
+			}
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []*IntSet{
+		New(NegInf(0)),
+		New(NegInf(5)),
+		New(NegInf(0), Int(3)),
+		New(StepNegInf(0, 2)),
+		New(StepNegInf(-1, 2)),
+		New(),
+		New(Int(1)),
+		New(Int(1), Int(2)),
+		New(Int(1), Int(3)),
+		New(Int(1), PosInf(3)),
+		New(Int(2)),
+		New(Range(2, 4)),
+		New(PosInf(100)),
+	}
+
+	for i, a := range ordered {
+		for j, b := range ordered {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if got := Compare(a, b); got != expected {
+				t.Fatalf("compare %s and %s failed: got %d, expected %d", a, b, got, expected)
+			}
+		}
+	}
+
+	shuffled := slices.Clone(ordered)
+	rand.New(rand.NewSource(5)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	slices.SortFunc(shuffled, Compare)
+	if got, expected := fmt.Sprint(shuffled), fmt.Sprint(ordered); got != expected {
+		t.Fatalf("sort failed: got %s, expected %s", got, expected)
+	}
+}
+
+// split returns the set with every element split into the two
+// progressions of twice its stride.
+func split(a *IntSet) *IntSet {
+	n := New()
+	for _, e := range a.elements {
+		lo, hi, neg, pos := e.bounds()
+		s, r := e.step(), e.residue()
+		if s > intMax/2 || (!e.inf() && lo == hi) {
+			n.AddElements(e)
+			continue
+		}
+		for _, x := range []int{r, r + s} {
+			if x := newStep(lo, hi, neg, pos, 2*s, x); x != nil {
+				n.AddElements(x)
+			}
+		}
+	}
+
+	return n
+}
+
+func TestPropertyKeyCompare(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for it := 0; it < 600; it++ {
+		base := bases[it%len(bases)]
+		a, b := randSet(r, base), randSet(r, base)
+
+		// the same sets built in different ways
+		sets := []*IntSet{
+			a, b,
+			a.Union(b), b.Union(a),
+			a.Difference(b).Union(a.Intersect(b)),
+			a.Complement().Complement(),
+			a.Xor(b).Xor(b),
+			a.Intersect(b), b.Complement().Union(a).Complement(),
+			split(a), split(split(a)), split(a.Union(b)),
+		}
+
+		keys := make([]string, len(sets))
+		cmp := make([][]int, len(sets))
+		for i, x := range sets {
+			keys[i] = x.Key()
+			cmp[i] = make([]int, len(sets))
+			for j, y := range sets {
+				cmp[i][j] = Compare(x, y)
+			}
+		}
+
+		for i, x := range sets {
+			for j, y := range sets {
+				equal := x.Equal(y)
+				if equal && x.Hash() != y.Hash() {
+					t.Fatalf("hash of %s and %s failed: got %d and %d", x, y, x.Hash(), y.Hash())
+				}
+				if (keys[i] == keys[j]) != equal {
+					t.Fatalf("key of %s and %s failed: got %s and %s, equal %t", x, y, keys[i], keys[j], equal)
+				}
+				if c := cmp[i][j]; (c == 0) != equal || c != -cmp[j][i] {
+					t.Fatalf("compare %s and %s failed: got %d and %d, equal %t", x, y, c, cmp[j][i], equal)
+				}
+				for k, z := range sets {
+					if cmp[i][j] < 0 && cmp[j][k] < 0 && cmp[i][k] >= 0 {
+						t.Fatalf("compare %s, %s and %s failed: not transitive", x, y, z)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package intset
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RangeClosedOpen returns a new element of the integers from a up to,
+// but not including, b, i.e. the interval [a,b) as used by Python
+// ranges and PostgreSQL. False is returned if the interval is empty,
+// as an element can not be empty.
+func RangeClosedOpen(a, b int) (*Element, bool) {
+	if b <= a {
+		return nil, false
+	}
+
+	return Range(a, b-1), true
+}
+
+// RangeOpenClosed returns a new element of the integers after a up to
+// and including b, i.e. the interval (a,b]. False is returned if the
+// interval is empty.
+func RangeOpenClosed(a, b int) (*Element, bool) {
+	if b <= a {
+		return nil, false
+	}
+
+	return Range(a+1, b), true
+}
+
+// RangeOpen returns a new element of the integers between a and b, not
+// including either, i.e. the interval (a,b). False is returned if the
+// interval is empty.
+func RangeOpen(a, b int) (*Element, bool) {
+	if b <= a || a+1 == b {
+		return nil, false
+	}
+
+	return Range(a+1, b-1), true
+}
+
+// BoundStyle selects how the bounds of intervals are written by
+// FormatIntervals.
+type BoundStyle int
+
+// The bound styles, where ClosedBounds writes [a,b], ClosedOpenBounds
+// writes [a,b), OpenClosedBounds writes (a,b] and OpenBounds writes
+// (a,b). Infinite bounds are always open.
+const (
+	ClosedBounds BoundStyle = iota
+	ClosedOpenBounds
+	OpenClosedBounds
+	OpenBounds
+)
+
+// limitPastMax and limitPastMin are the integers just outside the
+// limits of the platform int type, which are the open bounds of
+// ranges reaching the limits.
+var (
+	limitPastMax = strconv.FormatUint(uint64(intMax)+1, 10)
+	limitPastMin = "-" + strconv.FormatUint(uint64(intMax)+2, 10)
+)
+
+// FormatIntervals returns the set as a list of intervals with bounds
+// in the given style, e.g. {[1,6), [10,∞)} for ClosedOpenBounds. Finite
+// progressions are expanded into their ranges. An error is returned if
+// the set holds infinite progressions, as they are not intervals.
+func (a *IntSet) FormatIntervals(style BoundStyle) (string, error) {
+	b := a.expand()
+	if len(b.elements) == 0 {
+		return fmt.Sprintf("{%c}", 0x2205), nil
+	}
+
+	var ents []string
+	for _, e := range b.elements {
+		if e.stride > 1 {
+			return "", fmt.Errorf("intset: progression %s is not an interval", e)
+		}
+		ents = append(ents, formatInterval(e, style))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(ents, ", ")), nil
+}
+
+// formatInterval returns the range as an interval with bounds in the
+// given style.
+func formatInterval(e *Element, style BoundStyle) string {
+	lo, hi, neg, pos := e.bounds()
+
+	var from, to string
+	switch {
+	case neg:
+		from = "(-∞"
+	case style == OpenClosedBounds || style == OpenBounds:
+		from = "(" + limitPastMin
+		if lo != intMin {
+			from = "(" + strconv.Itoa(lo-1)
+		}
+	default:
+		from = "[" + strconv.Itoa(lo)
+	}
+	switch {
+	case pos:
+		to = "∞)"
+	case style == ClosedOpenBounds || style == OpenBounds:
+		to = limitPastMax + ")"
+		if hi != intMax {
+			to = strconv.Itoa(hi+1) + ")"
+		}
+	default:
+		to = strconv.Itoa(hi) + "]"
+	}
+
+	return from + "," + to
+}
+
+// ParseIntervals returns a new set from a list of intervals separated
+// by commas or white space, as returned by FormatIntervals. Every
+// interval is written [a,b], [a,b), (a,b] or (a,b), where a square
+// bracket includes the bound and a parenthesis excludes it. An empty
+// bound, ∞, inf or infinity, with an optional sign, is unbounded as in
+// PostgreSQL ranges, e.g. [10,). Empty intervals, also written empty
+// or ∅, are allowed. The enclosing braces are optional.
+func ParseIntervals(s string) (*IntSet, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("intset: missing closing brace in %q", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+
+	n := New()
+	for {
+		s = strings.TrimLeftFunc(s, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if s == "" {
+			return n, nil
+		}
+
+		if word, ok := cutWord(s, "empty", "∅"); ok {
+			s = s[len(word):]
+			continue
+		}
+
+		end := strings.IndexAny(s, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("intset: unterminated interval %q", s)
+		}
+		item := s[:end+1]
+		e, ok, err := parseInterval(item)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			n.insertElement(e)
+		}
+		s = s[end+1:]
+	}
+}
+
+// cutWord returns the word s starts with, and false if it starts with
+// none of the words.
+func cutWord(s string, words ...string) (string, bool) {
+	for _, w := range words {
+		if len(s) >= len(w) && strings.EqualFold(s[:len(w)], w) {
+			return s[:len(w)], true
+		}
+	}
+
+	return "", false
+}
+
+// parseInterval returns a new element from an interval written as
+// described by ParseIntervals, and false if the interval is empty.
+func parseInterval(s string) (*Element, bool, error) {
+	s = strings.TrimSpace(s)
+	if _, ok := cutWord(s, "empty", "∅"); ok && len(s) <= len("empty") {
+		return nil, false, nil
+	}
+	if len(s) < 3 || !strings.ContainsRune("[(", rune(s[0])) || !strings.ContainsRune("])", rune(s[len(s)-1])) {
+		return nil, false, fmt.Errorf("intset: invalid interval %q", s)
+	}
+	lower, upper, ok := strings.Cut(s[1:len(s)-1], ",")
+	if !ok {
+		return nil, false, fmt.Errorf("intset: invalid interval %q: missing comma", s)
+	}
+
+	lo, err := parseIntervalBound(lower, false)
+	if err != nil {
+		return nil, false, fmt.Errorf("intset: invalid interval %q: %v", s, err)
+	}
+	hi, err := parseIntervalBound(upper, true)
+	if err != nil {
+		return nil, false, fmt.Errorf("intset: invalid interval %q: %v", s, err)
+	}
+	if lo != nil && hi != nil && lo.Cmp(hi) > 0 {
+		return nil, false, fmt.Errorf("intset: invalid interval %q: reversed bounds", s)
+	}
+
+	// the integers of the interval are the closed bounds
+	one := big.NewInt(1)
+	if lo != nil && s[0] == '(' {
+		lo.Add(lo, one)
+	}
+	if hi != nil && s[len(s)-1] == ')' {
+		hi.Sub(hi, one)
+	}
+	if lo != nil && hi != nil && lo.Cmp(hi) > 0 {
+		return nil, false, nil
+	}
+
+	min, max := big.NewInt(int64(intMin)), big.NewInt(int64(intMax))
+	switch {
+	case lo != nil && lo.Cmp(min) < 0, hi != nil && hi.Cmp(max) > 0:
+		return nil, false, fmt.Errorf("intset: invalid interval %q: outside %d:%d", s, intMin, intMax)
+	case lo != nil && lo.Cmp(max) > 0, hi != nil && hi.Cmp(min) < 0:
+		// unbounded beyond the limits of the platform
+		return nil, false, nil
+	}
+
+	var l, h int
+	if lo != nil {
+		l = int(lo.Int64())
+	}
+	if hi != nil {
+		h = int(hi.Int64())
+	}
+
+	return fromBounds(l, h, lo == nil, hi == nil), true, nil
+}
+
+// parseIntervalBound returns the integer of a bound of an interval,
+// and nil if it is unbounded. The integer may be outside the limits
+// of the platform.
+func parseIntervalBound(s string, upper bool) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(strings.TrimLeft(s, "+-")) {
+	case "", "∞", "inf", "infinity":
+		if s != "" && strings.HasPrefix(s, "-") == upper {
+			return nil, fmt.Errorf("bad bound %q", s)
+		}
+		return nil, nil
+	}
+
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(s, "+"), 10)
+	if !ok {
+		return nil, fmt.Errorf("bad bound %q", s)
+	}
+
+	return n, nil
+}
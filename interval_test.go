@@ -0,0 +1,132 @@
+package intset
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestIntervalConstructors(t *testing.T) {
+	tests := []struct {
+		f        func(a, b int) (*Element, bool)
+		a, b     int
+		expected string
+	}{
+		{RangeClosedOpen, 1, 5, "1:4"},
+		{RangeClosedOpen, 5, 5, ""},
+		{RangeClosedOpen, 5, 1, ""},
+		{RangeClosedOpen, 10, intMax, fmt.Sprintf("10:%d", intMax-1)},
+		{RangeClosedOpen, intMin, intMin + 1, fmt.Sprintf("%d", intMin)},
+		{RangeOpenClosed, 1, 5, "2:5"},
+		{RangeOpenClosed, 5, 5, ""},
+		{RangeOpenClosed, intMin, 0, fmt.Sprintf("%d:0", intMin+1)},
+		{RangeOpenClosed, intMax - 1, intMax, fmt.Sprintf("%d", intMax)},
+		{RangeOpen, 1, 5, "2:4"},
+		{RangeOpen, 1, 2, ""},
+		{RangeOpen, 2, 1, ""},
+		{RangeOpen, intMin, intMax, fmt.Sprintf("%d:%d", intMin+1, intMax-1)},
+	}
+
+	for i, tc := range tests {
+		e, ok := tc.f(tc.a, tc.b)
+		if ok != (tc.expected != "") {
+			t.Fatalf("interval %d failed: got %t, expected %t", i, ok, !ok)
+		}
+		if ok && e.String() != tc.expected {
+			t.Fatalf("interval %d failed: got %s, expected %s", i, e, tc.expected)
+		}
+	}
+}
+
+func TestParseIntervals(t *testing.T) {
+	past := strconv.FormatUint(uint64(intMax)+1, 10)
+	pastMin := "-" + strconv.FormatUint(uint64(intMax)+2, 10)
+
+	tests := []struct {
+		s        string
+		expected string
+	}{
+		{"[1,5)", "{1:4}"},
+		{"(1,5]", "{2:5}"},
+		{"(1,5)", "{2:4}"},
+		{"[1,5]", "{1:5}"},
+		{"{[1,5), [10,)}", "{1:4, 10:∞}"},
+		{"(,0] [5,∞)", "{-∞:0, 5:∞}"},
+		{"(-inf, -3), (3, +infinity)", "{-∞:-4, 4:∞}"},
+		{"[5,5) empty (1,2) ∅", "{∅}"},
+		{"(,)", "{-∞:∞}"},
+		{fmt.Sprintf("[0,%s)", past), fmt.Sprintf("{0:%d}", intMax)},
+		{fmt.Sprintf("(%s,0)", pastMin), fmt.Sprintf("{%d:-1}", intMin)},
+		{fmt.Sprintf("(%d,)", intMax), "{∅}"},
+		{fmt.Sprintf("[%d,%d)", intMax, intMax), "{∅}"},
+	}
+
+	for _, tc := range tests {
+		a, err := ParseIntervals(tc.s)
+		if err != nil {
+			t.Fatalf("parse of %q failed: %v", tc.s, err)
+		}
+		if got := fmt.Sprintf("%s", a); got != tc.expected {
+			t.Fatalf("parse of %q failed: got %s, expected %s", tc.s, got, tc.expected)
+		}
+	}
+
+	for _, s := range []string{
+		"[5,1]",
+		"[5,1)",
+		"[1,5",
+		"[1;5]",
+		"{[1,5)",
+		"[x,5)",
+		"[∞,5)",
+		"[1,-∞)",
+		fmt.Sprintf("[0,%s]", past),
+		fmt.Sprintf("[%s,0]", pastMin),
+	} {
+		if _, err := ParseIntervals(s); err == nil {
+			t.Fatalf("parse of %q failed: got no error", s)
+		}
+	}
+}
+
+func TestFormatIntervals(t *testing.T) {
+	a := New(NegInf(-10), Range(1, 5), Step(10, 14, 2), PosInf(100))
+
+	tests := []struct {
+		style    BoundStyle
+		expected string
+	}{
+		{ClosedBounds, "{(-∞,-10], [1,5], [10,10], [12,12], [14,14], [100,∞)}"},
+		{ClosedOpenBounds, "{(-∞,-9), [1,6), [10,11), [12,13), [14,15), [100,∞)}"},
+		{OpenClosedBounds, "{(-∞,-10], (0,5], (9,10], (11,12], (13,14], (99,∞)}"},
+		{OpenBounds, "{(-∞,-9), (0,6), (9,11), (11,13), (13,15), (99,∞)}"},
+	}
+
+	for _, tc := range tests {
+		s, err := a.FormatIntervals(tc.style)
+		if err != nil || s != tc.expected {
+			t.Fatalf("format of %s failed: got %s, %v, expected %s", a, s, err, tc.expected)
+		}
+		if b, err := ParseIntervals(s); err != nil || !b.Equal(a) {
+			t.Fatalf("parse of %s failed: got %s, %v, expected %s", s, b, err, a)
+		}
+	}
+
+	limits := New(Int(intMin), Int(intMax))
+	for _, style := range []BoundStyle{ClosedBounds, ClosedOpenBounds, OpenClosedBounds, OpenBounds} {
+		s, err := limits.FormatIntervals(style)
+		if err != nil {
+			t.Fatalf("format of %s failed: %v", limits, err)
+		}
+		if b, err := ParseIntervals(s); err != nil || !b.Equal(limits) {
+			t.Fatalf("parse of %s failed: got %s, %v, expected %s", s, b, err, limits)
+		}
+	}
+
+	if s, err := New().FormatIntervals(ClosedOpenBounds); err != nil || s != "{∅}" {
+		t.Fatalf("format of empty set failed: got %s, %v", s, err)
+	}
+	if _, err := New(StepPosInf(0, 3)).FormatIntervals(ClosedBounds); err == nil {
+		t.Fatalf("format of infinite progression failed: got no error")
+	}
+}
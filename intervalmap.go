@@ -0,0 +1,228 @@
+package intset
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"strings"
+)
+
+// IntervalMap associates values with ranges of integers. It uses the
+// same range and infinity semantics as Element, so a value can be
+// associated with e.g. PosInf(1024). Adjacent ranges holding equal
+// values are joined into one.
+type IntervalMap[V any] struct {
+	entries []mapEntry[V]
+	equal   func(a, b V) bool
+}
+
+// mapEntry holds a range and its value.
+type mapEntry[V any] struct {
+	elem  *Element
+	value V
+}
+
+// NewIntervalMap returns a new empty interval map. The function equal
+// decides whether the values of two adjacent ranges are equal, in
+// which case the ranges are joined. If equal is nil, ranges are never
+// joined.
+func NewIntervalMap[V any](equal func(a, b V) bool) *IntervalMap[V] {
+	return &IntervalMap[V]{equal: equal}
+}
+
+// maxMapExpansion limits the number of integers of progressions set
+// or deleted integer by integer in an interval map.
+const maxMapExpansion = 1 << 16
+
+// Set associates v with the integers of e. Any overlapping spans
+// already in the map are overwritten, splitting them if needed.
+// Finite progressions are set integer by integer. An error is
+// returned for infinite progressions, as the spans between their
+// integers can not be held, and for progressions of more than 65536
+// integers.
+func (m *IntervalMap[V]) Set(e *Element, v V) error {
+	if e.stride > 1 {
+		if e.inf() {
+			return fmt.Errorf("intset: can not set infinite progression %s in interval map", e)
+		} else if e.count() > maxMapExpansion {
+			return fmt.Errorf("intset: progression %s too large for interval map", e)
+		}
+		if err := m.deleteStep(e); err != nil {
+			return err
+		}
+		m.insertPoints(e, v)
+		return nil
+	}
+
+	m.Delete(e)
+
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return lessElement(e, m.entries[i].elem)
+	})
+	m.entries = append(m.entries, mapEntry[V]{})
+	copy(m.entries[i+1:], m.entries[i:])
+	m.entries[i] = mapEntry[V]{elem: e, value: v}
+
+	m.joinAt(i + 1)
+	m.joinAt(i)
+
+	return nil
+}
+
+// insertPoints inserts the integers of the finite progression e with
+// the value v into the map, which must not hold any of them, and
+// joins the entries which became adjacent.
+func (m *IntervalMap[V]) insertPoints(e *Element, v V) {
+	entries := make([]mapEntry[V], 0, len(m.entries)+int(e.count()))
+	i, c := e.first, e.count()
+	for _, me := range m.entries {
+		for ; c > 0 && lessElement(Int(i), me.elem); i, c = i+e.stride, c-1 {
+			entries = append(entries, mapEntry[V]{elem: Int(i), value: v})
+		}
+		entries = append(entries, me)
+	}
+	for ; c > 0; i, c = i+e.stride, c-1 {
+		entries = append(entries, mapEntry[V]{elem: Int(i), value: v})
+	}
+	m.entries = entries
+
+	if m.equal == nil {
+		return
+	}
+	joined := m.entries[:0]
+	for _, me := range m.entries {
+		if n := len(joined); n > 0 && joined[n-1].elem.isAdjacent(me.elem) && m.equal(joined[n-1].value, me.value) {
+			joined[n-1].elem = joined[n-1].elem.join(me.elem)
+			continue
+		}
+		joined = append(joined, me)
+	}
+	m.entries = joined
+}
+
+// joinAt joins entry i with entry i-1 if they are adjacent and hold
+// equal values.
+func (m *IntervalMap[V]) joinAt(i int) {
+	if m.equal == nil || i <= 0 || i >= len(m.entries) {
+		return
+	}
+
+	p, e := m.entries[i-1], m.entries[i]
+	if !p.elem.isAdjacent(e.elem) || !m.equal(p.value, e.value) {
+		return
+	}
+	m.entries[i-1].elem = p.elem.join(e.elem)
+	m.entries = append(m.entries[:i], m.entries[i+1:]...)
+}
+
+// Get returns the value associated with n, and false if n is not in
+// the map.
+func (m *IntervalMap[V]) Get(n int) (V, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		_, hi, _, pos := m.entries[i].elem.bounds()
+		return pos || hi >= n
+	})
+	if i < len(m.entries) && m.entries[i].elem.has(n) {
+		return m.entries[i].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Delete removes the integers of e from the map, splitting spans if
+// needed. Progressions are deleted integer by integer, as the spans
+// must stay contiguous. An error is returned, and the map is left
+// unchanged, if an infinite progression meets an infinite span, or if
+// more than 65536 integers of a progression are in the map.
+func (m *IntervalMap[V]) Delete(e *Element) error {
+	if e.stride > 1 {
+		return m.deleteStep(e)
+	}
+
+	var entries []mapEntry[V]
+	for _, me := range m.entries {
+		for _, r := range me.elem.remove(e) {
+			entries = append(entries, mapEntry[V]{elem: r, value: me.value})
+		}
+	}
+	m.entries = entries
+
+	return nil
+}
+
+// deleteStep removes the integers of the progression e from the map,
+// one by one.
+func (m *IntervalMap[V]) deleteStep(e *Element) error {
+	points := make([][]*Element, len(m.entries))
+	count := uint(0)
+	for i, me := range m.entries {
+		for _, x := range me.elem.intersect(e) {
+			if x.inf() {
+				return fmt.Errorf("intset: can not delete infinite progression %s from interval map", e)
+			} else if count += x.count(); count > maxMapExpansion {
+				return fmt.Errorf("intset: progression %s too large for interval map", e)
+			}
+			points[i] = append(points[i], x)
+		}
+	}
+
+	var entries []mapEntry[V]
+	for i, me := range m.entries {
+		rest := []*Element{me.elem}
+		for _, x := range points[i] {
+			for n, c := x.first, x.count(); c > 0; n, c = n+x.step(), c-1 {
+				last := rest[len(rest)-1]
+				rest = append(rest[:len(rest)-1], last.remove(Int(n))...)
+			}
+		}
+		for _, r := range rest {
+			entries = append(entries, mapEntry[V]{elem: r, value: me.value})
+		}
+	}
+	m.entries = entries
+
+	return nil
+}
+
+// All returns an iterator over the maximal runs of the map and their
+// values in ascending order.
+func (m *IntervalMap[V]) All() iter.Seq2[*Element, V] {
+	return func(yield func(*Element, V) bool) {
+		for _, me := range m.entries {
+			if !yield(me.elem, me.value) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of runs in the map.
+func (m *IntervalMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// Domain returns the set of integers holding a value.
+func (m *IntervalMap[V]) Domain() *IntSet {
+	n := &IntSet{}
+	for _, me := range m.entries {
+		n.insertElement(me.elem)
+	}
+
+	return n
+}
+
+// String returns the map in a human readable form, in compliance with
+// the fmt.Stringer interface.
+func (m *IntervalMap[V]) String() string {
+	if len(m.entries) == 0 {
+		return fmt.Sprintf("{%c}", 0x2205)
+	}
+
+	var ents []string
+	for _, me := range m.entries {
+		ents = append(ents, fmt.Sprintf("%s: %v", me.elem, me.value))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(ents, ", "))
+}
@@ -0,0 +1,139 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIntervalMapSet(t *testing.T) {
+	m := NewIntervalMap(func(a, b string) bool { return a == b })
+	m.Set(Range(0, 1023), "system")
+	m.Set(PosInf(1024), "user")
+	m.Set(Range(80, 80), "http")
+	m.Set(Int(443), "https")
+	m.Set(Range(8000, 8100), "http-alt")
+
+	e := "{0:79: system, 80: http, 81:442: system, 443: https, 444:1023: system, 1024:7999: user, 8000:8100: http-alt, 8101:∞: user}"
+	if m.String() != e {
+		t.Fatalf("interval map set failed: got %s, expected %s", m, e)
+	}
+
+	m.Set(Range(8000, 8100), "user")
+	m.Set(Int(80), "system")
+	e = "{0:442: system, 443: https, 444:1023: system, 1024:∞: user}"
+	if m.String() != e {
+		t.Fatalf("interval map set failed: got %s, expected %s", m, e)
+	}
+}
+
+func TestIntervalMapGet(t *testing.T) {
+	m := NewIntervalMap[int](nil)
+	m.Set(NegInf(-1), 1)
+	m.Set(Range(0, 9), 2)
+	m.Set(Range(10, 19), 2)
+	m.Set(Step(30, 40, 5), 3)
+
+	for n, e := range map[int]int{-100: 1, 0: 2, 9: 2, 10: 2, 35: 3} {
+		if v, ok := m.Get(n); !ok || v != e {
+			t.Fatalf("interval map get failed: %d gave %d, expected %d", n, v, e)
+		}
+	}
+	for _, n := range []int{20, 31, 41} {
+		if v, ok := m.Get(n); ok {
+			t.Fatalf("interval map get failed: %d gave %d, expected nothing", n, v)
+		}
+	}
+	if m.Len() != 6 {
+		t.Fatalf("interval map get failed: got %d runs, expected 6 without equality function", m.Len())
+	}
+}
+
+func TestIntervalMapDelete(t *testing.T) {
+	m := NewIntervalMap(func(a, b int) bool { return a == b })
+	m.Set(All(), 0)
+	m.Delete(Range(-5, 5))
+	m.Set(Int(0), 1)
+
+	e := "{-∞:-6: 0, 0: 1, 6:∞: 0}"
+	if m.String() != e {
+		t.Fatalf("interval map delete failed: got %s, expected %s", m, e)
+	}
+
+	d := m.Domain()
+	e = "{-∞:-6, 0, 6:∞}"
+	if fmt.Sprintf("%s", d) != e {
+		t.Fatalf("interval map domain failed: got %s, expected %s", d, e)
+	}
+
+	p := NewIntervalMap(func(a, b string) bool { return a == b })
+	p.Set(Range(0, 20), "x")
+	p.Delete(Step(0, 20, 4))
+	for n := 0; n <= 20; n++ {
+		if v, ok := p.Get(n); ok != (n%4 != 0) {
+			t.Fatalf("interval map delete of progression failed: %d gave %q, %t", n, v, ok)
+		}
+	}
+	e = "{1:3: x, 5:7: x, 9:11: x, 13:15: x, 17:19: x}"
+	if p.String() != e {
+		t.Fatalf("interval map delete of progression failed: got %s, expected %s", p, e)
+	}
+
+	p.Delete(StepAll(1, 8))
+	e = "{2:3: x, 5:7: x, 10:11: x, 13:15: x, 18:19: x}"
+	if p.String() != e {
+		t.Fatalf("interval map delete of progression failed: got %s, expected %s", p, e)
+	}
+
+	p.Set(PosInf(100), "y")
+	if err := p.Delete(StepAll(1, 8)); err == nil || p.Len() != 6 {
+		t.Fatalf("interval map delete of infinite progression failed: got %v, %s", err, p)
+	}
+	if err := p.Set(StepPosInf(0, 2), "z"); err == nil || p.Len() != 6 {
+		t.Fatalf("interval map set of infinite progression failed: got %v, %s", err, p)
+	}
+
+	// progressions over spans with many integers
+	q := NewIntervalMap(func(a, b int) bool { return a == b })
+	q.Set(Range(-10, 10), 1)
+	if err := q.Set(Step(0, 40000, 2), 2); err != nil || q.Len() != 20007 {
+		t.Fatalf("interval map set of progression failed: got %v, %d runs", err, q.Len())
+	}
+	for n, e := range map[int]int{-10: 1, -1: 1, 0: 2, 1: 1, 9: 1, 10: 2, 40000: 2} {
+		if v, ok := q.Get(n); !ok || v != e {
+			t.Fatalf("interval map set of progression failed: %d gave %d, expected %d", n, v, e)
+		}
+	}
+	if v, ok := q.Get(11); ok {
+		t.Fatalf("interval map set of progression failed: 11 gave %d, expected nothing", v)
+	}
+	if err := q.Set(Step(0, 40000, 2), 1); err != nil || q.Len() != 19996 {
+		t.Fatalf("interval map set of progression failed: got %v, %d runs", err, q.Len())
+	}
+	if err := q.Set(Step(0, 1<<20, 2), 3); err == nil || q.Len() != 19996 {
+		t.Fatalf("interval map set of large progression failed: got %v, %d runs", err, q.Len())
+	}
+
+	q.Set(Range(0, intMax/2), 4)
+	if err := q.Delete(StepAll(1, 8)); err == nil || q.Len() != 2 {
+		t.Fatalf("interval map delete of progression from large span failed: got %v, %s", err, q)
+	}
+	if err := q.Delete(Step(1, 1<<16, 2)); err != nil || q.Len() != 1<<15+2 {
+		t.Fatalf("interval map delete of progression failed: got %v, %d runs", err, q.Len())
+	}
+}
+
+func TestIntervalMapAll(t *testing.T) {
+	m := NewIntervalMap(func(a, b int) bool { return a == b })
+	m.Set(Range(0, 9), 0)
+	m.Set(Range(10, 19), 1)
+	m.Set(Range(20, 29), 0)
+
+	var got []string
+	for e, v := range m.All() {
+		got = append(got, fmt.Sprintf("%s=%d", e, v))
+	}
+	e := "[0:9=0 10:19=1 20:29=0]"
+	if fmt.Sprint(got) != e {
+		t.Fatalf("interval map iteration failed: got %s, expected %s", got, e)
+	}
+}
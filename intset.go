@@ -29,105 +29,189 @@ package intset
 
 import (
 	"fmt"
+	"iter"
 	"strings"
 )
 
+// intMax and intMin holds the limits of the platform int type.
+const (
+	intMax = int(^uint(0) >> 1)
+	intMin = -intMax - 1
+)
+
 // IntSet holds a slice of element which makes a set.
 type IntSet struct {
-	elements []*Element
+	elements  []*Element
+	observers []*observer
+}
+
+// observer holds a function registered by Observe.
+type observer struct {
+	f func(added, removed *IntSet)
 }
 
 // New returns a new set. Any Range sets passed to New, will be added
 // to the set.
 func New(elements ...*Element) *IntSet {
 	n := &IntSet{}
-	n.AddElements(elements...)
+	for _, r := range elements {
+		n.insertElement(r)
+	}
 
 	return n
 }
 
-// AddInts adds integers to a set.
-func (a *IntSet) AddInts(numbers ...int) {
-	for _, n := range numbers {
-		a.insertElement(Int(n))
+// AddInts adds integers to a set, and returns the integers which were
+// not already in the set.
+func (a *IntSet) AddInts(numbers ...int) *IntSet {
+	elements := make([]*Element, len(numbers))
+	for i, n := range numbers {
+		elements[i] = Int(n)
 	}
-}
 
-// AddPosInf adds a range from n to ∞ to the set.
-func (a *IntSet) AddPosInf(n int) {
-	a.insertElement(PosInf(n))
+	return a.AddElements(elements...)
+}
 
+// AddPosInf adds a range from n to ∞ to the set, and returns the
+// integers which were not already in the set.
+func (a *IntSet) AddPosInf(n int) *IntSet {
+	return a.AddElements(PosInf(n))
 }
 
-// AddNegInf adds a range from -∞ to n to the set.
-func (a *IntSet) AddNegInf(n int) {
-	a.insertElement(NegInf(n))
+// AddNegInf adds a range from -∞ to n to the set, and returns the
+// integers which were not already in the set.
+func (a *IntSet) AddNegInf(n int) *IntSet {
+	return a.AddElements(NegInf(n))
 }
 
-// RemoveInts removes integers from a set.
-func (a *IntSet) RemoveInts(numbers ...int) {
-	for _, n := range numbers {
-		a.removeElement(Int(n))
+// RemoveInts removes integers from a set, and returns the integers
+// which were in the set.
+func (a *IntSet) RemoveInts(numbers ...int) *IntSet {
+	elements := make([]*Element, len(numbers))
+	for i, n := range numbers {
+		elements[i] = Int(n)
 	}
+
+	return a.RemoveElements(elements...)
 }
 
-// AddElements adds element types of all kinds to a set.
-func (a *IntSet) AddElements(elements ...*Element) {
+// AddElements adds element types of all kinds to a set, and returns
+// the integers which were not already in the set.
+func (a *IntSet) AddElements(elements ...*Element) *IntSet {
+	added := New()
 	for _, r := range elements {
-		a.insertElement(r)
+		for _, e := range a.insertElement(r) {
+			added.insertElement(e)
+		}
 	}
+	a.notify(added, New())
+
+	return added
 }
 
-// RemoveElements removes elements from a set.
-func (a *IntSet) RemoveElements(elements ...*Element) {
+// RemoveElements removes elements from a set, and returns the integers
+// which were in the set.
+func (a *IntSet) RemoveElements(elements ...*Element) *IntSet {
+	removed := New()
 	for _, r := range elements {
-		a.removeElement(r)
+		for _, e := range a.removeElement(r) {
+			removed.insertElement(e)
+		}
 	}
+	a.notify(New(), removed)
+
+	return removed
 }
 
-// insertRange inserts a single Range to a set.
-func (a *IntSet) insertElement(r *Element) {
-	if len(a.elements) == 0 {
-		a.elements = append(a.elements, r)
+// Observe registers a function which is called with the integers
+// added to and removed from the set whenever its members change
+// through AddInts, AddPosInf, AddNegInf, RemoveInts, AddElements,
+// RemoveElements, ReadFrom or Apply. Functions are called in the order
+// they were registered, and must not modify the set or the sets they
+// are given. Copies of the set are not observed. The returned function
+// cancels the registration.
+func (a *IntSet) Observe(f func(added, removed *IntSet)) func() {
+	o := &observer{f: f}
+	a.observers = append(a.observers, o)
+
+	return func() {
+		for i, p := range a.observers {
+			if p == o {
+				a.observers = append(a.observers[:i:i], a.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notify calls the observers of the set, unless nothing was changed.
+func (a *IntSet) notify(added, removed *IntSet) {
+	if len(added.elements) == 0 && len(removed.elements) == 0 {
 		return
+	}
+	for _, o := range a.observers {
+		o.f(added, removed)
+	}
+}
+
+// insertRange inserts a single Range to a set, and returns the parts
+// of it which were not already in the set.
+func (a *IntSet) insertElement(r *Element) []*Element {
+	if r.stride > 1 || a.strided() {
+		return a.insertStep(r)
+	} else if len(a.elements) == 0 {
+		a.elements = append(a.elements, r)
+		return []*Element{r}
 	} else if len(a.elements) == 1 && a.elements[0].all { // special case for 'all'
-		return
+		return nil
 	}
 
 	var newList []*Element
-	var prev *Element
 
+	added := []*Element{r}
 	inserted := false
-	for i, e := range a.elements {
-		if inserted {
-			// overlap check mode
-			if prev.isOverlapping(e) {
-				a.elements[i-1] = prev.join(e)
-				continue
-			}
-		} else {
-			// insert mode
+	for _, e := range a.elements {
+		if !inserted {
 			if e.isOverlapping(r) || e.isAdjacent(r) {
-				a.elements[i] = e.join(r)
-
-				inserted = true
-			} else if r.first < e.first {
+				if e.isOverlapping(r) {
+					added = removeFrom(added, e)
+				}
+				// keep joining until r is placed
+				r = e.join(r)
+				continue
+			} else if lessElement(r, e) {
 				newList = append(newList, r)
 				inserted = true
 			}
 		}
-		prev = a.elements[i]
-		newList = append(newList, a.elements[i])
+		newList = append(newList, e)
 	}
 	if !inserted {
 		newList = append(newList, r)
 	}
 
 	a.elements = newList
+
+	return added
+}
+
+// removeFrom returns the parts of the elements not in o.
+func removeFrom(elements []*Element, o *Element) []*Element {
+	var ret []*Element
+	for _, e := range elements {
+		ret = append(ret, e.remove(o)...)
+	}
+
+	return ret
 }
 
 // optimize range sets
 func (a *IntSet) optimize() {
+	if a.strided() {
+		a.normalize()
+		return
+	}
+
 	// keep joining ranges until no more ranges can be joined
 	for {
 		n2 := &IntSet{}
@@ -142,15 +226,25 @@ func (a *IntSet) optimize() {
 	}
 }
 
-// removeElement removes a single element from a set.
-func (a *IntSet) removeElement(r *Element) {
-	var newList []*Element
+// removeElement removes a single element from a set, and returns the
+// parts of the set which were removed.
+func (a *IntSet) removeElement(r *Element) []*Element {
+	var newList, removed []*Element
 	for _, e := range a.elements {
-		for _, n := range e.remove(r) {
-			newList = append(newList, n)
+		if !e.isOverlapping(r) {
+			newList = append(newList, e)
+			continue
 		}
+		newList = append(newList, e.remove(r)...)
+		removed = append(removed, e.intersect(r)...)
 	}
 	a.elements = newList
+
+	if a.strided() {
+		a.normalize()
+	}
+
+	return removed
 }
 
 // String returns the set in a human readable form, in compliance with
@@ -168,6 +262,18 @@ func (a *IntSet) String() string {
 	return fmt.Sprintf("{%s}", strings.Join(ents, ", "))
 }
 
+// Elements returns an iterator over the elements of the set in
+// ascending order.
+func (a *IntSet) Elements() iter.Seq[*Element] {
+	return func(yield func(*Element) bool) {
+		for _, r := range a.elements {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
 // HasInt returns true if the integer is part of the set.
 func (a *IntSet) HasInt(m int) bool {
 	if len(a.elements) == 0 {
@@ -175,10 +281,7 @@ func (a *IntSet) HasInt(m int) bool {
 	}
 
 	for _, r := range a.elements {
-		if r.all ||
-			(r.neginf && m <= r.first) ||
-			(r.posinf && m >= r.first) ||
-			(m >= r.first && m <= r.last) {
+		if r.has(m) {
 			return true
 		}
 	}
@@ -186,6 +289,33 @@ func (a *IntSet) HasInt(m int) bool {
 	return false
 }
 
+// NextAfter returns the smallest integer of the set greater than n,
+// and false if there is none.
+func (a *IntSet) NextAfter(n int) (int, bool) {
+	if n == intMax {
+		return 0, false
+	}
+
+	next, found := 0, false
+	for _, r := range a.elements {
+		lo, hi, neg, pos := r.bounds()
+		c := n + 1
+		if !neg && lo > c {
+			c = lo
+		} else if r.step() > 1 {
+			var ok bool
+			if c, ok = alignUp(c, r.residue(), r.step()); !ok {
+				continue
+			}
+		}
+		if (pos || c <= hi) && (!found || c < next) {
+			next, found = c, true
+		}
+	}
+
+	return next, found
+}
+
 // Cardinality returns an unsigned integer holding the cardinality and
 // an infinite boolean. If the infinite boolean is true, the
 // cardinality of the set can not be held by the unsigned integer, and
@@ -197,7 +327,11 @@ func (a *IntSet) Cardinality() (uint, bool) {
 		if r.inf() {
 			return 0, true
 		}
-		if r.last > 0 && r.first < 0 {
+		if r.stride > 1 {
+			if uintAddOverflow(&cardinality, r.count()) {
+				return 0, true
+			}
+		} else if r.last > 0 && r.first < 0 {
 			if uintAddOverflow(&cardinality, uint(r.last)) ||
 				uintAddOverflow(&cardinality, uint(r.first*-1)) ||
 				uintAddOverflow(&cardinality, 1) {
@@ -226,44 +360,25 @@ func uintAddOverflow(p *uint, n uint) bool {
 
 // Complement returns a∁.
 func (a *IntSet) Complement() *IntSet {
+	if a.strided() {
+		return New(All()).Difference(a)
+	}
+
 	n := &IntSet{}
 
-	if len(a.elements) == 0 {
-		n.elements = append(n.elements, &Element{all: true})
-		return n
-	} else if len(a.elements) == 1 {
-		l := a.elements[0]
-		if l.all {
-			a.elements = nil
-		} else if l.neginf {
-			n.elements = append(n.elements, &Element{first: l.first + 1, posinf: true})
-		} else if l.posinf {
-			n.elements = append(n.elements, &Element{first: l.first - 1, neginf: true})
-		} else {
-			n.elements = append(n.elements, &Element{first: l.first - 1, neginf: true})
-			n.elements = append(n.elements, &Element{first: l.last + 1, posinf: true})
+	// lo holds the start of the next gap, unless neg is set
+	lo, neg := 0, true
+	for _, e := range a.elements {
+		elo, ehi, eneg, epos := e.bounds()
+		if !eneg && !(neg && elo == intMin) {
+			n.elements = append(n.elements, fromBounds(lo, elo-1, neg, false))
 		}
-		return n
-	}
-
-	last := len(a.elements) - 2
-	prev := a.elements[0]
-	for i, e := range a.elements[1:] {
-		if i == 0 {
-			if !prev.inf() {
-				n.elements = append(n.elements, &Element{first: prev.first - 1, neginf: true})
-				n.elements = append(n.elements, &Element{first: prev.last + 1, last: e.first - 1})
-			} else {
-				n.elements = append(n.elements, &Element{first: prev.first + 1, last: e.first - 1})
-			}
-		} else if i == last && !e.inf() {
-			n.elements = append(n.elements, &Element{first: prev.last + 1, last: e.last - 1})
-			n.elements = append(n.elements, &Element{first: e.last + 1, posinf: true})
-		} else {
-			n.elements = append(n.elements, &Element{first: prev.last + 1, last: e.first - 1})
+		if epos || ehi == intMax {
+			return n
 		}
-		prev = e
+		lo, neg = ehi+1, false
 	}
+	n.elements = append(n.elements, fromBounds(lo, 0, neg, true))
 
 	return n
 }
@@ -290,7 +405,7 @@ func (a *IntSet) Intersect(b *IntSet) *IntSet {
 	for _, ar := range a.elements {
 		for _, br := range b.elements {
 			for _, e := range ar.intersect(br) {
-				n.AddElements(e)
+				n.insertElement(e)
 			}
 		}
 	}
@@ -301,7 +416,9 @@ func (a *IntSet) Intersect(b *IntSet) *IntSet {
 // Difference returns a - b.
 func (a *IntSet) Difference(b *IntSet) *IntSet {
 	n := a.Copy()
-	n.RemoveElements(b.elements...)
+	for _, r := range b.elements {
+		n.removeElement(r)
+	}
 	n.optimize()
 
 	return n
@@ -314,15 +431,18 @@ func (a *IntSet) Xor(b *IntSet) *IntSet {
 
 // Copy returns a copy hf the set.
 func (a *IntSet) Copy() *IntSet {
-	n := &IntSet{}
-	n.AddElements(a.elements...)
-
-	return n
+	return New(a.elements...)
 }
 
-// Equal returns true if the two sets are equal.
+// Equal returns true if the two sets are equal. Sets holding the same
+// integers of the platform differ if their infinite ends do, as for
+// a:∞ and a:MaxInt.
 func (a *IntSet) Equal(b *IntSet) bool {
-	if len(a.elements) != len(b.elements) {
+	if a.strided() || b.strided() {
+		return sameIntegers(a, b) &&
+			sameIntegers(endSet(a), endSet(b)) &&
+			sameIntegers(posEndSet(a), posEndSet(b))
+	} else if len(a.elements) != len(b.elements) {
 		return false
 	}
 
@@ -335,6 +455,12 @@ func (a *IntSet) Equal(b *IntSet) bool {
 	return true
 }
 
+// sameIntegers returns true if the two sets hold the same integers of
+// the platform.
+func sameIntegers(a, b *IntSet) bool {
+	return len(a.Difference(b).elements) == 0 && len(b.Difference(a).elements) == 0
+}
+
 // IsSubsetOf returns true if a ⊆ b.
 func (a *IntSet) IsSubsetOf(b *IntSet) bool {
 	return a.Union(b).Equal(b)
@@ -2,6 +2,7 @@ package intset
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -72,6 +73,30 @@ func TestIntSetRemove2(t *testing.T) {
 	}
 }
 
+func TestIntSetCompose7(t *testing.T) {
+	tests := []struct {
+		a        *IntSet
+		expected string
+	}{
+		{New(Range(1, 3), Range(4, 6)), "{1:6}"},
+		{New(Range(4, 6), Range(1, 3)), "{1:6}"},
+		{New(Range(1, 3), Range(7, 9), Range(4, 6)), "{1:9}"},
+		{New(Int(1), Int(3), Int(2)), "{1:3}"},
+		{New(NegInf(0), Range(1, 5)), "{-∞:5}"},
+		{New(Range(-5, -1), NegInf(-6)), "{-∞:-1}"},
+		{New(Range(-30, -20), NegInf(-40), Range(-39, -31)), "{-∞:-20}"},
+		{New(Range(-20, -15), NegInf(-10)), "{-∞:-10}"},
+		{New(NegInf(0), PosInf(1)), "{-∞:∞}"},
+		{New(Int(intMax), Int(intMin)), fmt.Sprintf("{%d, %d}", intMin, intMax)},
+	}
+
+	for _, tc := range tests {
+		if got := fmt.Sprintf("%s", tc.a); got != tc.expected {
+			t.Fatalf("compose failed: got %s, expected %s", got, tc.expected)
+		}
+	}
+}
+
 func TestComplement1(t *testing.T) {
 	a := New(All())
 	e := "{∅}"
@@ -104,6 +129,26 @@ func TestComplement4(t *testing.T) {
 	}
 }
 
+func TestComplement5(t *testing.T) {
+	tests := []struct {
+		a        *IntSet
+		expected string
+	}{
+		{New(Range(1, 3), Range(7, 9)), "{-∞:0, 4:6, 10:∞}"},
+		{New(NegInf(-10), Range(0, 5), PosInf(20)), "{-9:-1, 6:19}"},
+		{New(Int(0), Range(4, 5), Range(9, 10)), "{-∞:-1, 1:3, 6:8, 11:∞}"},
+	}
+
+	for _, tc := range tests {
+		if got := fmt.Sprintf("%s", tc.a.Complement()); got != tc.expected {
+			t.Fatalf("complement failed: %s%c, got %s, expected %s", tc.a, 0x2201, got, tc.expected)
+		}
+		if got := tc.a.Complement().Complement(); !got.Equal(tc.a) {
+			t.Fatalf("complement failed: %s%c%c, got %s, expected %s", tc.a, 0x2201, 0x2201, got, tc.a)
+		}
+	}
+}
+
 func TestUnion1(t *testing.T) {
 	a := New(Range(-400, -200), Range(-199, -34), Range(400, 420), Range(50, 399), Range(49, 101), PosInf(500), NegInf(-5000))
 	b := New(Range(-400, -200), Range(-199, -34), Range(400, 420), Range(50, 399), Range(49, 101), PosInf(500), NegInf(-5000))
@@ -131,6 +176,26 @@ func TestIntersect1(t *testing.T) {
 	}
 }
 
+func TestIntersect2(t *testing.T) {
+	tests := []struct {
+		a, b     *IntSet
+		expected string
+	}{
+		{New(All()), New(Range(-5, 5)), "{-5:5}"},
+		{New(Range(-5, 5)), New(All()), "{-5:5}"},
+		{New(All()), New(NegInf(3), PosInf(7)), "{-∞:3, 7:∞}"},
+		{New(NegInf(3), PosInf(7)), New(All()), "{-∞:3, 7:∞}"},
+		{New(All()), New(All()), "{-∞:∞}"},
+		{New(All()), New(), "{∅}"},
+	}
+
+	for _, tc := range tests {
+		if got := fmt.Sprintf("%s", tc.a.Intersect(tc.b)); got != tc.expected {
+			t.Fatalf("intersection failed: %s %c %s, got %s, expected %s", tc.a, 0x2229, tc.b, got, tc.expected)
+		}
+	}
+}
+
 func TestDifference1(t *testing.T) {
 	a := New(Range(-90, 5), Range(90, 100))
 	b := New(Range(-50, 50))
@@ -207,3 +272,112 @@ func TestXor(t *testing.T) {
 		t.Fatalf("xor failed: %s %c %s, got %s, expected %s", a, 0x22bb, b, a.Xor(b), e)
 	}
 }
+
+func TestElements(t *testing.T) {
+	a := New(PosInf(25), Range(-10, -5), Int(3))
+
+	var ents []string
+	for e := range a.Elements() {
+		ents = append(ents, fmt.Sprintf("%s", e))
+		if len(ents) == 2 {
+			break
+		}
+	}
+
+	e := "-10:-5 3"
+	if g := strings.Join(ents, " "); g != e {
+		t.Fatalf("elements failed: %s, got %s, expected %s", a, g, e)
+	}
+}
+
+func TestNextAfter(t *testing.T) {
+	a := New(NegInf(-10), Range(5, 10), Step(20, 40, 10), StepPosInf(101, 7), StepPosInf(100, 3))
+
+	tests := []struct {
+		n, next int
+		ok      bool
+	}{
+		{-100, -99, true},
+		{-11, -10, true},
+		{-10, 5, true},
+		{7, 8, true},
+		{10, 20, true},
+		{20, 30, true},
+		{40, 100, true},
+		{100, 101, true},
+		{101, 103, true},
+		{107, 108, true},
+		{intMax - 1, intMax, true},
+		{intMax, 0, false},
+	}
+
+	for _, tc := range tests {
+		if next, ok := a.NextAfter(tc.n); next != tc.next || ok != tc.ok {
+			t.Fatalf("next of %s after %d failed: got %d, %t, expected %d, %t", a, tc.n, next, ok, tc.next, tc.ok)
+		}
+	}
+
+	if _, ok := New().NextAfter(0); ok {
+		t.Fatalf("next of empty set failed: got true")
+	}
+}
+
+func TestMutationDelta(t *testing.T) {
+	a := New(Range(1, 10), PosInf(100))
+
+	tests := []struct {
+		delta    *IntSet
+		expected string
+		set      string
+	}{
+		{a.AddElements(Range(5, 15), Int(200)), "{11:15}", "{1:15, 100:∞}"},
+		{a.AddInts(3, 20, 21), "{20:21}", "{1:15, 20:21, 100:∞}"},
+		{a.AddInts(7), "{∅}", "{1:15, 20:21, 100:∞}"},
+		{a.RemoveElements(Range(12, 30)), "{12:15, 20:21}", "{1:11, 100:∞}"},
+		{a.RemoveInts(50, 1), "{1}", "{2:11, 100:∞}"},
+		{a.RemoveElements(Step(0, 12, 4)), "{4:8:4}", "{2:3, 5:7, 9:11, 100:∞}"},
+		{a.AddNegInf(5), "{-∞:1, 4}", "{-∞:7, 9:11, 100:∞}"},
+		{a.AddPosInf(50), "{50:99}", "{-∞:7, 9:11, 50:∞}"},
+	}
+
+	for i, tc := range tests {
+		if got := fmt.Sprintf("%s", tc.delta); got != tc.expected {
+			t.Fatalf("delta %d failed: got %s, expected %s", i, got, tc.expected)
+		}
+	}
+	if got := fmt.Sprintf("%s", a); got != tests[len(tests)-1].set {
+		t.Fatalf("mutations failed: got %s, expected %s", got, tests[len(tests)-1].set)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	a := New(Range(1, 10))
+
+	var log []string
+	cancel := a.Observe(func(added, removed *IntSet) {
+		log = append(log, fmt.Sprintf("+%s -%s", added, removed))
+	})
+
+	a.AddInts(5, 11)
+	a.AddInts(1)
+	a.RemoveElements(Range(9, 20))
+	a.Copy().AddInts(100)
+	if err := a.Apply(Patch{Added: New(Int(0)), Removed: New(Int(8))}); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if _, err := a.ReadFrom(strings.NewReader("3,30")); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	cancel()
+	a.AddInts(40)
+
+	expected := []string{
+		"+{11} -{∅}",
+		"+{∅} -{9:11}",
+		"+{0} -{8}",
+		"+{30} -{∅}",
+	}
+	if got, want := strings.Join(log, "; "), strings.Join(expected, "; "); got != want {
+		t.Fatalf("observe failed: got %s, expected %s", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+// Package intsettest implements helpers for testing code using
+// integer sets: a configurable generator of random sets, and an
+// assertion telling how two sets differ.
+package intsettest
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/stianwa/intset"
+)
+
+// Generator generates random sets within a window of integers. The
+// fields may be changed to configure the sets generated.
+type Generator struct {
+	// Min and Max are the window of the finite integers of the
+	// sets.
+	Min, Max int
+
+	// Density is the expected share of the window held by the sets,
+	// from 0 to 1.
+	Density float64
+
+	// InfiniteEnds is the probability of a set running to -∞ from
+	// Min, and likewise of a set running from Max to ∞.
+	InfiniteEnds float64
+
+	// Strided is the probability of a run of the set being a
+	// progression with a stride from 2 to MaxStride rather than a
+	// range.
+	Strided   float64
+	MaxStride int
+}
+
+// NewGenerator returns a new generator of sets within -100 to 100,
+// with a density of 0.5 and no infinite ends or progressions.
+func NewGenerator() *Generator {
+	return &Generator{Min: -100, Max: 100, Density: 0.5, MaxStride: 4}
+}
+
+// Set returns a random set. The window is split into runs of random
+// length, and every run is part of the set with the probability of the
+// density.
+func (g *Generator) Set(r *rand.Rand) *intset.IntSet {
+	lo, hi := g.Min, g.Max
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	maxRun := int64((uint64(hi)-uint64(lo))/8) + 1
+
+	a := intset.New()
+	for pos := lo; ; {
+		end := hi
+		if n := uint64(r.Int63n(maxRun)); n < uint64(hi)-uint64(pos) {
+			end = pos + int(n)
+		}
+		if r.Float64() < g.Density {
+			if g.MaxStride >= 2 && r.Float64() < g.Strided {
+				a.AddElements(intset.Step(pos, end, 2+r.Intn(g.MaxStride-1)))
+			} else {
+				a.AddElements(intset.Range(pos, end))
+			}
+		}
+		if end == hi {
+			break
+		}
+		pos = end + 1
+	}
+
+	if r.Float64() < g.InfiniteEnds {
+		a.AddElements(intset.NegInf(lo))
+	}
+	if r.Float64() < g.InfiniteEnds {
+		a.AddElements(intset.PosInf(hi))
+	}
+
+	return a
+}
+
+// Values sets every argument to a random set. It is meant for
+// quick.Config.Values when checking functions taking sets only, e.g.
+//
+//	quick.Check(f, &quick.Config{Values: g.Values})
+func (g *Generator) Values(args []reflect.Value, r *rand.Rand) {
+	for i := range args {
+		args[i] = reflect.ValueOf(g.Set(r))
+	}
+}
+
+// Equal reports an error through t if got and want are not equal
+// sets, telling which integers are missing from got and which are
+// extra. It returns true if the sets are equal.
+func Equal(t testing.TB, got, want *intset.IntSet) bool {
+	t.Helper()
+
+	if got.Equal(want) {
+		return true
+	}
+
+	t.Errorf("sets differ:\n got: %s\nwant: %s\nmissing: %s\n  extra: %s",
+		got, want, want.Difference(got), got.Difference(want))
+
+	return false
+}
@@ -0,0 +1,90 @@
+package intsettest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stianwa/intset"
+)
+
+func TestGenerator(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := NewGenerator()
+	g.Min, g.Max = 0, 999
+
+	var total uint
+	for i := 0; i < 200; i++ {
+		a := g.Set(r)
+		if !a.IsSubsetOf(intset.New(intset.Range(0, 999))) {
+			t.Fatalf("set failed: %s is outside the window", a)
+		}
+		c, _ := a.Cardinality()
+		total += c
+	}
+	if d := float64(total) / 200 / 1000; d < 0.4 || d > 0.6 {
+		t.Fatalf("set failed: got density %.2f, expected 0.5", d)
+	}
+
+	g.Density, g.InfiniteEnds, g.Strided = 1, 1, 1
+	a := g.Set(r)
+	if a.HasInt(500) && a.HasInt(501) || !a.HasInt(-5000) || !a.HasInt(5000) {
+		t.Fatalf("set failed: %s is not strided with infinite ends", a)
+	}
+
+	g = &Generator{Min: math.MinInt, Max: math.MaxInt, Density: 0.5}
+	for i := 0; i < 10; i++ {
+		g.Set(r)
+	}
+	g = &Generator{Min: 5, Max: 5, Density: 1}
+	if a := g.Set(r); fmt.Sprintf("%s", a) != "{5}" {
+		t.Fatalf("set failed: got %s, expected {5}", a)
+	}
+}
+
+func TestValues(t *testing.T) {
+	g := NewGenerator()
+	g.InfiniteEnds, g.Strided = 0.3, 0.3
+
+	f := func(a, b *intset.IntSet) bool {
+		return a.Xor(b).Equal(a.Difference(b).Union(b.Difference(a)))
+	}
+	if err := quick.Check(f, &quick.Config{Values: g.Values}); err != nil {
+		t.Fatalf("values failed: %v", err)
+	}
+}
+
+// recorder records errors reported through it.
+type recorder struct {
+	testing.TB
+	msg string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+func TestEqual(t *testing.T) {
+	r := &recorder{TB: t}
+	got := intset.New(intset.Range(1, 10), intset.Int(20))
+	want := intset.New(intset.Range(1, 5), intset.Range(8, 12))
+
+	if Equal(r, got, want) {
+		t.Fatalf("equal failed: %s and %s reported equal", got, want)
+	}
+	for _, e := range []string{"missing: {11:12}", "extra: {6:7, 20}"} {
+		if !strings.Contains(r.msg, e) {
+			t.Fatalf("equal failed: got %q, expected it to hold %q", r.msg, e)
+		}
+	}
+
+	r.msg = ""
+	if !Equal(r, got, got.Copy()) || r.msg != "" {
+		t.Fatalf("equal failed: %s reported unequal to itself: %s", got, r.msg)
+	}
+}
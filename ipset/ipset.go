@@ -0,0 +1,259 @@
+//go:build amd64 || arm64 || loong64 || mips64 || mips64le || ppc64 || ppc64le || riscv64 || s390x || wasm
+
+// Package ipset implements sets of IPv4 addresses on top of
+// intset.IntSet, where every address is held as its 32-bit integer.
+// The sets are bounded to the addresses from 0.0.0.0 to
+// 255.255.255.255. Since every address must fit an int, the package is
+// only built on platforms with 64-bit integers.
+package ipset
+
+import (
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"strings"
+
+	"github.com/stianwa/intset"
+)
+
+// maxAddr is the integer of 255.255.255.255.
+const maxAddr = 1<<32 - 1
+
+// Set is a set of IPv4 addresses. The zero value is not usable, use
+// New.
+type Set struct {
+	s *intset.IntSet
+}
+
+// New returns a new empty set.
+func New() *Set {
+	return &Set{s: intset.New()}
+}
+
+// FromIntSet returns a new set of the integers of a within the IPv4
+// address space.
+func FromIntSet(a *intset.IntSet) *Set {
+	return &Set{s: a.Clamp(0, maxAddr)}
+}
+
+// IntSet returns the addresses of the set as integers.
+func (s *Set) IntSet() *intset.IntSet {
+	return s.s.Copy()
+}
+
+// Parse returns a new set from a list of addresses, ranges written
+// from-to and prefixes separated by commas or white space, e.g.
+// 10.0.0.0/8, 192.168.1.10-192.168.1.20, 172.16.0.1.
+func Parse(str string) (*Set, error) {
+	s := New()
+	for _, item := range strings.FieldsFunc(str, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		var err error
+		if from, to, ok := strings.Cut(item, "-"); ok {
+			var f, t netip.Addr
+			if f, err = netip.ParseAddr(from); err == nil {
+				if t, err = netip.ParseAddr(to); err == nil {
+					err = s.AddRange(f, t)
+				}
+			}
+		} else if strings.Contains(item, "/") {
+			var p netip.Prefix
+			if p, err = netip.ParsePrefix(item); err == nil {
+				err = s.AddPrefix(p)
+			}
+		} else {
+			var a netip.Addr
+			if a, err = netip.ParseAddr(item); err == nil {
+				err = s.AddAddr(a)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ipset: invalid item %q: %v", item, err)
+		}
+	}
+
+	return s, nil
+}
+
+// toInt returns the integer of an IPv4 address, or an IPv4 address
+// mapped to IPv6.
+func toInt(a netip.Addr) (int, error) {
+	a = a.Unmap()
+	if !a.Is4() {
+		return 0, fmt.Errorf("ipset: %s is not an IPv4 address", a)
+	}
+	b := a.As4()
+
+	return int(b[0])<<24 | int(b[1])<<16 | int(b[2])<<8 | int(b[3]), nil
+}
+
+// toAddr returns the IPv4 address of an integer.
+func toAddr(n int) netip.Addr {
+	return netip.AddrFrom4([4]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+}
+
+// AddAddr adds the address to the set.
+func (s *Set) AddAddr(a netip.Addr) error {
+	n, err := toInt(a)
+	if err != nil {
+		return err
+	}
+	s.s.AddInts(n)
+
+	return nil
+}
+
+// AddRange adds the addresses from one address to another to the set.
+// The addresses may be given in any order.
+func (s *Set) AddRange(from, to netip.Addr) error {
+	f, err := toInt(from)
+	if err != nil {
+		return err
+	}
+	t, err := toInt(to)
+	if err != nil {
+		return err
+	}
+	s.s.AddElements(intset.Range(f, t))
+
+	return nil
+}
+
+// AddPrefix adds the addresses of the prefix to the set. The host bits
+// of the prefix are ignored.
+func (s *Set) AddPrefix(p netip.Prefix) error {
+	f, t, err := prefixRange(p)
+	if err != nil {
+		return err
+	}
+	s.s.AddElements(intset.Range(f, t))
+
+	return nil
+}
+
+// RemovePrefix removes the addresses of the prefix from the set.
+func (s *Set) RemovePrefix(p netip.Prefix) error {
+	f, t, err := prefixRange(p)
+	if err != nil {
+		return err
+	}
+	s.s.RemoveElements(intset.Range(f, t))
+
+	return nil
+}
+
+// prefixRange returns the first and last integer of the prefix.
+func prefixRange(p netip.Prefix) (int, int, error) {
+	if !p.IsValid() {
+		return 0, 0, fmt.Errorf("ipset: invalid prefix %s", p)
+	}
+	if p.Addr().Is4In6() {
+		if p.Bits() < 96 {
+			return 0, 0, fmt.Errorf("ipset: %s is not an IPv4 prefix", p)
+		}
+		p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	f, err := toInt(p.Masked().Addr())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return f, f | (1<<(32-p.Bits()) - 1), nil
+}
+
+// Contains returns true if the address is part of the set.
+func (s *Set) Contains(a netip.Addr) bool {
+	n, err := toInt(a)
+	return err == nil && s.s.HasInt(n)
+}
+
+// Union returns a new set holding the addresses of s and b.
+func (s *Set) Union(b *Set) *Set {
+	return &Set{s: s.s.Union(b.s)}
+}
+
+// Intersect returns a new set holding the addresses of both s and b.
+func (s *Set) Intersect(b *Set) *Set {
+	return &Set{s: s.s.Intersect(b.s)}
+}
+
+// Difference returns a new set holding the addresses of s not in b.
+func (s *Set) Difference(b *Set) *Set {
+	return &Set{s: s.s.Difference(b.s)}
+}
+
+// Complement returns a new set holding the IPv4 addresses not in s.
+func (s *Set) Complement() *Set {
+	return &Set{s: s.s.Complement().Clamp(0, maxAddr)}
+}
+
+// Equal returns true if the two sets hold the same addresses.
+func (s *Set) Equal(b *Set) bool {
+	return s.s.Equal(b.s)
+}
+
+// Size returns the number of addresses in the set.
+func (s *Set) Size() uint64 {
+	c, _ := s.s.Cardinality()
+	return uint64(c)
+}
+
+// ranges returns the ranges of the set.
+func (s *Set) ranges() [][2]int {
+	// sets are bounded, and never infinite
+	r, _ := s.s.ToRanges()
+	return r
+}
+
+// Ranges returns the first and last address of every range of the set
+// in ascending order.
+func (s *Set) Ranges() [][2]netip.Addr {
+	var ret [][2]netip.Addr
+	for _, r := range s.ranges() {
+		ret = append(ret, [2]netip.Addr{toAddr(r[0]), toAddr(r[1])})
+	}
+
+	return ret
+}
+
+// Prefixes returns the shortest list of prefixes covering exactly the
+// addresses of the set, in ascending order.
+func (s *Set) Prefixes() []netip.Prefix {
+	var ret []netip.Prefix
+	for _, r := range s.ranges() {
+		lo, hi := uint64(r[0]), uint64(r[1])
+		for lo <= hi {
+			// the largest block aligned at lo within the range
+			size := 32
+			if lo != 0 {
+				size = bits.TrailingZeros64(lo)
+			}
+			for lo+1<<size-1 > hi {
+				size--
+			}
+			ret = append(ret, netip.PrefixFrom(toAddr(int(lo)), 32-size))
+			lo += 1 << size
+		}
+	}
+
+	return ret
+}
+
+// String returns the set as addresses and ranges of addresses, in
+// compliance with the fmt.Stringer interface.
+func (s *Set) String() string {
+	var ents []string
+	for _, r := range s.Ranges() {
+		if r[0] == r[1] {
+			ents = append(ents, r[0].String())
+		} else {
+			ents = append(ents, r[0].String()+"-"+r[1].String())
+		}
+	}
+	if len(ents) == 0 {
+		return fmt.Sprintf("{%c}", 0x2205)
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(ents, ", "))
+}
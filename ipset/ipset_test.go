@@ -0,0 +1,105 @@
+//go:build amd64 || arm64 || loong64 || mips64 || mips64le || ppc64 || ppc64le || riscv64 || s390x || wasm
+
+package ipset
+
+import (
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/stianwa/intset"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse("10.0.0.0/8, 192.168.1.20-192.168.1.10 172.16.0.1,10.1.2.3/32")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	e := "{10.0.0.0-10.255.255.255, 172.16.0.1, 192.168.1.10-192.168.1.20}"
+	if s.String() != e {
+		t.Fatalf("parse failed: got %s, expected %s", s, e)
+	}
+
+	for _, str := range []string{"10.0.0.0/33", "::1", "2001:db8::/32", "1.2.3", "1.2.3.4-::1"} {
+		if _, err := Parse(str); err == nil {
+			t.Fatalf("parse %q failed: got no error", str)
+		}
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.10-192.168.1.20":   "[192.168.1.10/31 192.168.1.12/30 192.168.1.16/30 192.168.1.20/32]",
+		"10.0.0.0/8, 11.0.0.0/8":      "[10.0.0.0/7]",
+		"0.0.0.0-255.255.255.255":     "[0.0.0.0/0]",
+		"255.255.255.255":             "[255.255.255.255/32]",
+		"0.0.0.1-255.255.255.254":     "",
+		"10.0.0.0/24, 10.0.0.128/25 ": "[10.0.0.0/24]",
+		"":                            "[]",
+	}
+
+	for str, e := range tests {
+		s, err := Parse(str)
+		if err != nil {
+			t.Fatalf("parse %q failed: %v", str, err)
+		}
+		p := s.Prefixes()
+		if e == "" {
+			// 31 prefixes on either side of 128.0.0.0
+			if len(p) != 62 {
+				t.Fatalf("prefixes of %s failed: got %d prefixes, expected 62", s, len(p))
+			}
+			continue
+		}
+		if fmt.Sprintf("%v", p) != e {
+			t.Fatalf("prefixes of %s failed: got %v, expected %s", s, p, e)
+		}
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a, _ := Parse("10.0.0.0/8")
+	b, _ := Parse("10.128.0.0/9, 192.168.0.0/16")
+
+	if s, e := a.Intersect(b).String(), "{10.128.0.0-10.255.255.255}"; s != e {
+		t.Fatalf("intersect failed: got %s, expected %s", s, e)
+	}
+	if s, e := a.Difference(b).String(), "{10.0.0.0-10.127.255.255}"; s != e {
+		t.Fatalf("difference failed: got %s, expected %s", s, e)
+	}
+	if s, e := a.Union(b).String(), "{10.0.0.0-10.255.255.255, 192.168.0.0-192.168.255.255}"; s != e {
+		t.Fatalf("union failed: got %s, expected %s", s, e)
+	}
+	if s, e := fmt.Sprintf("%v", a.Complement().Prefixes()), "[0.0.0.0/5 8.0.0.0/7 11.0.0.0/8 12.0.0.0/6 16.0.0.0/4 32.0.0.0/3 64.0.0.0/2 128.0.0.0/1]"; s != e {
+		t.Fatalf("complement failed: got %s, expected %s", s, e)
+	}
+	if !New().Complement().Complement().Equal(New()) {
+		t.Fatalf("complement of complement of empty set failed")
+	}
+	if c := New().Complement().Size(); c != 1<<32 {
+		t.Fatalf("size of all addresses failed: got %d", c)
+	}
+
+	if !a.Contains(netip.MustParseAddr("10.1.2.3")) || !a.Contains(netip.MustParseAddr("::ffff:10.1.2.3")) || a.Contains(netip.MustParseAddr("11.0.0.0")) {
+		t.Fatalf("contains failed for %s", a)
+	}
+
+	if err := a.RemovePrefix(netip.MustParsePrefix("10.0.0.0/9")); err != nil || a.String() != "{10.128.0.0-10.255.255.255}" {
+		t.Fatalf("remove prefix failed: got %s, %v", a, err)
+	}
+	if err := a.AddPrefix(netip.MustParsePrefix("::ffff:1.2.3.0/120")); err != nil || !a.Contains(netip.MustParseAddr("1.2.3.200")) {
+		t.Fatalf("add mapped prefix failed: got %s, %v", a, err)
+	}
+	if err := a.AddAddr(netip.MustParseAddr("::1")); err == nil {
+		t.Fatalf("add of IPv6 address failed: got no error")
+	}
+
+	s := FromIntSet(intset.New(intset.NegInf(5), intset.PosInf(1<<32-2)))
+	if e := "{0.0.0.0-0.0.0.5, 255.255.255.254-255.255.255.255}"; s.String() != e {
+		t.Fatalf("from int set failed: got %s, expected %s", s, e)
+	}
+	if e := "{0:5, 4294967294:4294967295}"; fmt.Sprintf("%s", s.IntSet()) != e {
+		t.Fatalf("int set failed: got %s, expected %s", s.IntSet(), e)
+	}
+}
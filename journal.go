@@ -0,0 +1,245 @@
+package intset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrCheckpoint is returned when rolling back to a checkpoint which is
+// no longer in the journal, i.e. when it was undone and the set was
+// changed afterwards.
+var ErrCheckpoint = errors.New("intset: checkpoint not in journal")
+
+// JournaledIntSet is a set which records every change of its members
+// as a patch, so that changes may be undone and redone. The journal
+// and the set may be saved and restored through JSON.
+type JournaledIntSet struct {
+	set  *IntSet
+	undo []journalEntry
+	redo []journalEntry
+
+	// seq is the sequence number of the last recorded change
+	seq uint64
+}
+
+// journalEntry is a change recorded in the journal.
+type journalEntry struct {
+	Seq   uint64 `json:"seq"`
+	Patch Patch  `json:"patch"`
+}
+
+// NewJournaledIntSet returns a new journaled set holding a copy of a,
+// with an empty journal. A nil set is the empty set.
+func NewJournaledIntSet(a *IntSet) *JournaledIntSet {
+	if a == nil {
+		a = New()
+	}
+
+	return &JournaledIntSet{set: a.Copy()}
+}
+
+// IntSet returns a copy of the set.
+func (j *JournaledIntSet) IntSet() *IntSet {
+	return j.set.Copy()
+}
+
+// String returns the set in a human readable form, in compliance with
+// the fmt.Stringer interface.
+func (j *JournaledIntSet) String() string {
+	return j.set.String()
+}
+
+// record adds the patch to the journal and forgets the changes which
+// were undone, unless the patch is empty.
+func (j *JournaledIntSet) record(p Patch) {
+	if p.IsEmpty() {
+		return
+	}
+	j.seq++
+	j.undo = append(j.undo, journalEntry{Seq: j.seq, Patch: p})
+	j.redo = nil
+}
+
+// AddElements adds elements to the set, and returns the integers which
+// were not already in the set.
+func (j *JournaledIntSet) AddElements(elements ...*Element) *IntSet {
+	added := j.set.AddElements(elements...)
+	j.record(Patch{Added: added})
+
+	return added
+}
+
+// RemoveElements removes elements from the set, and returns the
+// integers which were in the set.
+func (j *JournaledIntSet) RemoveElements(elements ...*Element) *IntSet {
+	removed := j.set.RemoveElements(elements...)
+	j.record(Patch{Removed: removed})
+
+	return removed
+}
+
+// assign replaces the set with n, and records the change.
+func (j *JournaledIntSet) assign(n *IntSet) {
+	p := Diff(j.set, n)
+	j.set = n
+	j.record(p)
+}
+
+// UnionWith sets the set to a ∪ b.
+func (j *JournaledIntSet) UnionWith(b *IntSet) {
+	j.assign(j.set.Union(b))
+}
+
+// IntersectWith sets the set to a ∩ b.
+func (j *JournaledIntSet) IntersectWith(b *IntSet) {
+	j.assign(j.set.Intersect(b))
+}
+
+// DifferenceWith sets the set to a - b.
+func (j *JournaledIntSet) DifferenceWith(b *IntSet) {
+	j.assign(j.set.Difference(b))
+}
+
+// XorWith sets the set to a ⊻ b.
+func (j *JournaledIntSet) XorWith(b *IntSet) {
+	j.assign(j.set.Xor(b))
+}
+
+// ComplementSet sets the set to a∁.
+func (j *JournaledIntSet) ComplementSet() {
+	j.assign(j.set.Complement())
+}
+
+// patch applies the patch to the set. Patches of the journal always
+// apply, so they are not checked as by Apply.
+func (j *JournaledIntSet) patch(p Patch) {
+	j.set = j.set.Difference(p.removed()).Union(p.added())
+}
+
+// Undo undoes the last change of the set, and returns false if there
+// is none.
+func (j *JournaledIntSet) Undo() bool {
+	if len(j.undo) == 0 {
+		return false
+	}
+
+	e := j.undo[len(j.undo)-1]
+	j.undo = j.undo[:len(j.undo)-1]
+	j.patch(e.Patch.Invert())
+	j.redo = append(j.redo, e)
+
+	return true
+}
+
+// Redo redoes the last change undone, and returns false if there is
+// none.
+func (j *JournaledIntSet) Redo() bool {
+	if len(j.redo) == 0 {
+		return false
+	}
+
+	e := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+	j.patch(e.Patch)
+	j.undo = append(j.undo, e)
+
+	return true
+}
+
+// Checkpoint returns a checkpoint of the current state of the set, to
+// be passed to RollbackTo. The checkpoint of a set with no changes to
+// undo is 0.
+func (j *JournaledIntSet) Checkpoint() uint64 {
+	if len(j.undo) == 0 {
+		return 0
+	}
+
+	return j.undo[len(j.undo)-1].Seq
+}
+
+// RollbackTo returns the set to the state of the checkpoint, undoing
+// the changes made after it, or redoing the changes up to it if it was
+// undone. The changes may be redone or undone again. ErrCheckpoint is
+// returned, and the set is left unchanged, if the checkpoint is no
+// longer in the journal.
+func (j *JournaledIntSet) RollbackTo(checkpoint uint64) error {
+	undo, redo := checkpoint == 0, false
+	for _, e := range j.undo {
+		undo = undo || e.Seq == checkpoint
+	}
+	for _, e := range j.redo {
+		redo = redo || e.Seq == checkpoint
+	}
+
+	switch {
+	case undo:
+		for j.Checkpoint() != checkpoint {
+			j.Undo()
+		}
+	case redo:
+		for j.Checkpoint() != checkpoint {
+			j.Redo()
+		}
+	default:
+		return ErrCheckpoint
+	}
+
+	return nil
+}
+
+// journalJSON is the JSON form of a journaled set.
+type journalJSON struct {
+	Set  []string       `json:"set"`
+	Seq  uint64         `json:"seq"`
+	Undo []journalEntry `json:"undo"`
+	Redo []journalEntry `json:"redo"`
+}
+
+// MarshalJSON returns the set and its journal as a JSON object, where
+// the set is a list of elements in the notation of Parse and the
+// changes are patches, in compliance with the json.Marshaler
+// interface.
+func (j *JournaledIntSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(journalJSON{
+		Set:  elementStrings(j.set),
+		Seq:  j.seq,
+		Undo: append([]journalEntry{}, j.undo...),
+		Redo: append([]journalEntry{}, j.redo...),
+	})
+}
+
+// UnmarshalJSON sets the set and its journal from their JSON form, in
+// compliance with the json.Unmarshaler interface.
+func (j *JournaledIntSet) UnmarshalJSON(data []byte) error {
+	var v journalJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	set, err := parseElements(v.Set)
+	if err != nil {
+		return err
+	}
+
+	// changes to undo are in ascending order, and changes to redo
+	// in descending order, with none after the last one recorded
+	var last uint64
+	for _, e := range v.Undo {
+		if e.Seq <= last || e.Seq > v.Seq {
+			return fmt.Errorf("intset: invalid journal sequence %d", e.Seq)
+		}
+		last = e.Seq
+	}
+	last = v.Seq + 1
+	for _, e := range v.Redo {
+		if e.Seq >= last || len(v.Undo) > 0 && e.Seq <= v.Undo[len(v.Undo)-1].Seq {
+			return fmt.Errorf("intset: invalid journal sequence %d", e.Seq)
+		}
+		last = e.Seq
+	}
+
+	j.set, j.seq, j.undo, j.redo = set, v.Seq, v.Undo, v.Redo
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package intset
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestJournalUndoRedo(t *testing.T) {
+	j := NewJournaledIntSet(New(Range(1, 10)))
+
+	steps := []struct {
+		change   func()
+		expected string
+	}{
+		{func() { j.AddElements(Range(5, 15)) }, "{1:15}"},
+		{func() { j.RemoveElements(Int(3), Int(100)) }, "{1:2, 4:15}"},
+		{func() { j.UnionWith(New(PosInf(20))) }, "{1:2, 4:15, 20:∞}"},
+		{func() { j.IntersectWith(New(Range(0, 30))) }, "{1:2, 4:15, 20:30}"},
+		{func() { j.DifferenceWith(New(Step(0, 30, 2))) }, "{1, 5:15:2, 21:29:2}"},
+		{func() { j.XorWith(New(Range(1, 5))) }, "{2:4, 7:15:2, 21:29:2}"},
+		{func() { j.ComplementSet() }, "{-∞:1, 5:6, 8:14:2, 16:20, 22:28:2, 30:∞}"},
+	}
+
+	for _, s := range steps {
+		s.change()
+		if got := fmt.Sprintf("%s", j); got != s.expected {
+			t.Fatalf("change failed: got %s, expected %s", got, s.expected)
+		}
+	}
+
+	// empty changes are not recorded
+	j.AddElements(Int(0))
+	for i := len(steps) - 2; i >= 0; i-- {
+		if !j.Undo() {
+			t.Fatalf("undo failed: got false")
+		}
+		if got := fmt.Sprintf("%s", j); got != steps[i].expected {
+			t.Fatalf("undo failed: got %s, expected %s", got, steps[i].expected)
+		}
+	}
+	if !j.Undo() || j.Undo() || j.String() != "{1:10}" {
+		t.Fatalf("undo of first change failed: got %s", j)
+	}
+
+	for _, s := range steps {
+		if !j.Redo() {
+			t.Fatalf("redo failed: got false")
+		}
+		if got := fmt.Sprintf("%s", j); got != s.expected {
+			t.Fatalf("redo failed: got %s, expected %s", got, s.expected)
+		}
+	}
+	if j.Redo() {
+		t.Fatalf("redo past last change failed: got true")
+	}
+
+	j.Undo()
+	j.AddElements(Int(1000))
+	if j.Redo() {
+		t.Fatalf("redo after change failed: got true")
+	}
+}
+
+func TestJournalCheckpoint(t *testing.T) {
+	j := NewJournaledIntSet(nil)
+	j.AddElements(Range(1, 5))
+	cp := j.Checkpoint()
+	j.AddElements(Int(10))
+	j.RemoveElements(Int(2))
+	last := j.Checkpoint()
+
+	if err := j.RollbackTo(cp); err != nil || j.String() != "{1:5}" {
+		t.Fatalf("rollback failed: got %s, %v", j, err)
+	}
+	// the checkpoint was undone, and is reached by redoing
+	if err := j.RollbackTo(last); err != nil || j.String() != "{1, 3:5, 10}" {
+		t.Fatalf("rollback to undone checkpoint failed: got %s, %v", j, err)
+	}
+	j.Undo()
+	if err := j.RollbackTo(cp); err != nil || j.String() != "{1:5}" {
+		t.Fatalf("rollback failed: got %s, %v", j, err)
+	}
+	if !j.Redo() || j.String() != "{1:5, 10}" {
+		t.Fatalf("redo after rollback failed: got %s", j)
+	}
+
+	late := j.Checkpoint()
+	j.Undo()
+	j.AddElements(Int(20))
+	if err := j.RollbackTo(late); err != ErrCheckpoint || j.String() != "{1:5, 20}" {
+		t.Fatalf("rollback to stale checkpoint failed: got %s, %v", j, err)
+	}
+	if err := j.RollbackTo(0); err != nil || j.String() != "{∅}" {
+		t.Fatalf("rollback to start failed: got %s, %v", j, err)
+	}
+}
+
+func TestJournalJSON(t *testing.T) {
+	j := NewJournaledIntSet(New(NegInf(0)))
+	j.AddElements(Step(10, 20, 5))
+	j.RemoveElements(Range(-5, -1))
+	j.AddElements(PosInf(100))
+	j.Undo()
+
+	data, err := json.Marshal(j)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	expected := `{"set":["-∞:-6","0","10:20:5"],"seq":3,` +
+		`"undo":[{"seq":1,"patch":{"added":["10:20:5"],"removed":[]}},{"seq":2,"patch":{"added":[],"removed":["-5:-1"]}}],` +
+		`"redo":[{"seq":3,"patch":{"added":["100:∞"],"removed":[]}}]}`
+	if string(data) != expected {
+		t.Fatalf("marshal failed: got %s, expected %s", data, expected)
+	}
+
+	r := &JournaledIntSet{}
+	if err := json.Unmarshal(data, r); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !r.Redo() || r.String() != "{-∞:-6, 0, 10:20:5, 100:∞}" {
+		t.Fatalf("redo after unmarshal failed: got %s", r)
+	}
+	if err := r.RollbackTo(0); err != nil || r.String() != "{-∞:0}" {
+		t.Fatalf("rollback after unmarshal failed: got %s, %v", r, err)
+	}
+
+	for _, s := range []string{
+		`{"set":["x"]}`,
+		`{"set":[],"seq":1,"undo":[{"seq":2,"patch":{}}]}`,
+		`{"set":[],"seq":2,"undo":[{"seq":2,"patch":{}}],"redo":[{"seq":1,"patch":{}}]}`,
+	} {
+		if err := json.Unmarshal([]byte(s), &JournaledIntSet{}); err == nil {
+			t.Fatalf("unmarshal of %s failed: got no error", s)
+		}
+	}
+}
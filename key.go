@@ -0,0 +1,538 @@
+package intset
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// maxKeyBlock limits the number of runs of a block written once by
+// Key when it repeats.
+const maxKeyBlock = 64
+
+// Key returns a canonical encoding of the integers of the set, usable
+// as a map key: sets have the same key if and only if they are Equal.
+// The key is derived from the integers alone, so it does not depend on
+// how the set was built. The integers are written as runs with a common
+// distance, found from the smallest integer up, where blocks of runs
+// repeating at a fixed distance are written once. Infinite ends are
+// written as their periodic patterns over the smallest period, with
+// the integer where the set departs from each of them.
+func (a *IntSet) Key() string {
+	if len(a.elements) == 0 {
+		return a.String()
+	}
+
+	// the set agrees with its negative pattern below lo, and with its
+	// positive pattern above hi, or on the whole platform if not found
+	var b strings.Builder
+	b.WriteByte('{')
+	lo, hi, loFound, hiFound := intMin, intMax, true, true
+	neg, pos := endSet(a), posEndSet(a)
+	if len(neg.elements) > 0 {
+		writePattern(&b, neg)
+		if lo, _, loFound = boundsOf(a.Xor(neg)); loFound {
+			fmt.Fprintf(&b, "<%d;", lo)
+		} else {
+			fmt.Fprintf(&b, "<%c;", 0x221e)
+		}
+	}
+	if len(pos.elements) > 0 {
+		_, hi, hiFound = boundsOf(a.Xor(pos))
+	}
+
+	if loFound && hiFound && lo <= hi {
+		writeRuns(&b, a.Clamp(lo, hi))
+	}
+
+	if len(pos.elements) > 0 {
+		if hiFound {
+			fmt.Fprintf(&b, ";%d>", hi)
+		} else {
+			fmt.Fprintf(&b, ";-%c>", 0x221e)
+		}
+		writePattern(&b, pos)
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// boundsOf returns the smallest and the largest integer of the set
+// within the limits of the platform, and false if it has none.
+func boundsOf(a *IntSet) (int, int, bool) {
+	c := a.Clamp(intMin, intMax)
+	if len(c.elements) == 0 {
+		return 0, 0, false
+	}
+
+	lo, hi := intMax, intMin
+	for _, e := range c.elements {
+		lo, hi = smallestOf(lo, e.first), largestOf(hi, e.last)
+	}
+
+	return lo, hi, true
+}
+
+// posEndSet returns the periodic pattern of the positive end of the
+// set, as a set of progressions infinite in both directions.
+func posEndSet(a *IntSet) *IntSet {
+	n := New()
+	for _, e := range a.elements {
+		if e.posinf || e.all {
+			n.insertElement(newStep(0, 0, true, true, e.step(), e.residue()))
+		}
+	}
+
+	return n
+}
+
+// writePattern writes the periodic set p as its smallest period d and
+// its integers from 0 to d-1.
+func writePattern(b *strings.Builder, p *IntSet) {
+	d := period(p)
+	if d == 0 {
+		b.WriteString(p.String())
+		return
+	}
+	fmt.Fprintf(b, "%d%c+[", d, 0x2124)
+	writeRuns(b, p.Clamp(0, d-1))
+	b.WriteByte(']')
+}
+
+// period returns the smallest period of the set p of progressions
+// infinite in both directions, found by dividing the common period of
+// the progressions by its prime factors while the set repeats.
+func period(p *IntSet) int {
+	d := 1
+	for _, e := range p.elements {
+		var ok bool
+		if d, ok = lcm(d, e.step()); !ok {
+			// beyond the platform, where the set can not repeat
+			return 0
+		}
+	}
+
+	for _, q := range primeFactors(d) {
+		for d%q == 0 {
+			s, err := p.Shift(d / q)
+			if err != nil || !sameIntegers(p, s) {
+				break
+			}
+			d /= q
+		}
+	}
+
+	return d
+}
+
+// keyRun is a run of integers with a common distance, as found by
+// runScanner.
+type keyRun struct {
+	first  int
+	count  uint
+	stride uint
+}
+
+// String returns the run in the form used by Element.
+func (r keyRun) String() string {
+	return runString(uint(r.first), r.count, r.stride, true)
+}
+
+// runString returns a run from first, written as a signed integer if
+// signed, else as an offset.
+func runString(first, count, stride uint, signed bool) string {
+	f := func(n uint) string {
+		if signed {
+			return fmt.Sprintf("%d", int(n))
+		}
+		return fmt.Sprintf("%d", n)
+	}
+
+	last := first + (count-1)*stride
+	switch {
+	case count == 1:
+		return f(first)
+	case stride == 1:
+		return f(first) + ":" + f(last)
+	}
+	return fmt.Sprintf("%s:%s:%d", f(first), f(last), stride)
+}
+
+// writeRuns writes the integers of the finite set m as runs, from the
+// smallest integer up. Each run holds the integers from its first
+// with the distance to the next integer of the set, for as long as
+// the set holds them and nothing between them. A block of up to
+// maxKeyBlock runs followed by the same block shifted by its length
+// is written once, with the number of times the set repeats it.
+func writeRuns(b *strings.Builder, m *IntSet) {
+	sc := newRunScanner(m)
+	x, more := sc.next(intMin)
+	var runs []keyRun
+	sep, done := "", false
+	for more {
+		for !done && len(runs) < 2*maxKeyBlock+1 {
+			if len(runs) == 0 {
+				runs = append(runs, sc.run(x))
+				continue
+			}
+			r := runs[len(runs)-1]
+			last := uint(r.first) + (r.count-1)*r.stride
+			n, ok := 0, false
+			if last != uint(intMax) {
+				n, ok = sc.next(int(last + 1))
+			}
+			if done = !ok; !done {
+				runs = append(runs, sc.run(n))
+			}
+		}
+
+		b.WriteString(sep)
+		sep = ", "
+
+		k := repeatedBlock(runs)
+		if k == 0 {
+			b.WriteString(runs[0].String())
+			if runs = runs[1:]; len(runs) == 0 {
+				break
+			}
+			x = runs[0].first
+			continue
+		}
+
+		t := uint(runs[k].first) - uint(x)
+		copies := sc.periodEnd(x, t)/t + 1
+		var block []string
+		for _, r := range runs[:k] {
+			block = append(block, runString(uint(r.first)-uint(x), r.count, r.stride, false))
+		}
+		fmt.Fprintf(b, "%d+[%s]*%d:%d", x, strings.Join(block, " "), t, copies)
+
+		end := uint(x) + copies*t
+		runs, done = runs[:0], false
+		if end-1 == uint(intMax) {
+			break
+		}
+		x, more = sc.next(int(end))
+	}
+}
+
+// repeatedBlock returns the smallest k such that the first k runs are
+// followed by the same k runs shifted by the distance between the
+// first runs of the two blocks, and then by a further run at the same
+// distance again, or 0 if there is no such k.
+func repeatedBlock(runs []keyRun) int {
+	gap := func(i int) uint {
+		return uint(runs[i+1].first) - uint(runs[i].first)
+	}
+	for k := 1; k <= maxKeyBlock && 2*k < len(runs); k++ {
+		same := true
+		for i := 0; i < k && same; i++ {
+			same = runs[i].count == runs[k+i].count && runs[i].stride == runs[k+i].stride && gap(i) == gap(k+i)
+		}
+		if same {
+			return k
+		}
+	}
+
+	return 0
+}
+
+// runScanner answers the queries of writeRuns about a finite set.
+type runScanner struct {
+	set      *IntSet
+	elements []*Element
+	strided  bool
+}
+
+// newRunScanner returns a scanner of the finite set m, with its
+// elements ordered by their smallest integers.
+func newRunScanner(m *IntSet) *runScanner {
+	es := append([]*Element(nil), m.elements...)
+	sort.SliceStable(es, func(i, j int) bool { return es[i].first < es[j].first })
+
+	return &runScanner{set: m, elements: es, strided: m.strided()}
+}
+
+// start returns the index of the first element that may hold integers
+// from x, which is 0 for strided sets, whose elements interleave.
+func (sc *runScanner) start(x int) int {
+	if sc.strided {
+		return 0
+	}
+
+	return sort.Search(len(sc.elements), func(i int) bool { return sc.elements[i].last >= x })
+}
+
+// next returns the smallest integer of the set not below x, and false
+// if there is none.
+func (sc *runScanner) next(x int) (int, bool) {
+	n, found := 0, false
+	for _, e := range sc.elements[sc.start(x):] {
+		if found && e.first >= n {
+			break
+		} else if e.last < x {
+			continue
+		}
+		c := e.first
+		if c < x {
+			var ok bool
+			if c, ok = alignUp(x, e.residue(), e.step()); !ok || c > e.last {
+				continue
+			}
+		}
+		if !found || c < n {
+			n, found = c, true
+		}
+	}
+
+	return n, found
+}
+
+// has returns true if the set holds n.
+func (sc *runScanner) has(n int) bool {
+	for _, e := range sc.elements[sc.start(n):] {
+		if e.first > n {
+			break
+		} else if e.has(n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// run returns the run starting at the integer x of the set.
+func (sc *runScanner) run(x int) keyRun {
+	if x == intMax {
+		return keyRun{first: x, count: 1, stride: 1}
+	}
+	n, ok := sc.next(x + 1)
+	if !ok {
+		return keyRun{first: x, count: 1, stride: 1}
+	}
+
+	s := uint(n) - uint(x)
+	if s > uint(intMax) {
+		return keyRun{first: x, count: 2, stride: s}
+	}
+
+	return keyRun{first: x, count: sc.agreement(x, s)/s + 1, stride: s}
+}
+
+// agreement returns the distance from x to the last integer up to
+// which the set agrees with the progression from x with stride s,
+// before an integer held by only one of them, or before the limit of
+// the platform.
+func (sc *runScanner) agreement(x int, s uint) uint {
+	bound := uint(intMax) - uint(x)
+
+	// integers of the set off the progression
+	for _, e := range sc.elements[sc.start(x+1):] {
+		if e.first > x && uint(e.first)-uint(x) > bound {
+			break
+		} else if e.last <= x {
+			continue
+		}
+		c := e.first
+		if c <= x {
+			var ok bool
+			if c, ok = alignUp(x+1, e.residue(), e.step()); !ok || c > e.last {
+				continue
+			}
+		}
+		off := uint(c) - uint(x)
+		if off%s != 0 {
+			bound = smallestOfUint(bound, off-1)
+		} else if uint(e.step())%s != 0 && uint(e.last)-uint(c) >= uint(e.step()) {
+			bound = smallestOfUint(bound, off+uint(e.step())-1)
+		}
+	}
+
+	// integers of the progression off the set
+	if !sc.strided {
+		// skip along the ranges holding the progression
+		for off := s; off <= bound; {
+			p := int(uint(x) + off)
+			i := sc.start(p)
+			if i == len(sc.elements) || sc.elements[i].first > p {
+				return off - 1
+			}
+			reach := uint(sc.elements[i].last) - uint(x)
+			if reach >= bound || bound-reach < s {
+				break
+			}
+			off += (reach-off)/s*s + s
+		}
+		return bound
+	}
+	if bound >= s {
+		c := newStep(x, int(uint(x)+bound), false, false, int(s), modInt(x, int(s)))
+		for _, e := range New(c).Difference(sc.set).elements {
+			bound = smallestOfUint(bound, uint(e.first)-uint(x)-1)
+		}
+	}
+
+	return bound
+}
+
+// periodEnd returns the distance from x to the first integer z not
+// below x where the set holds only one of z and z+t, or to the first z
+// where z+t is past the limit of the platform.
+func (sc *runScanner) periodEnd(x int, t uint) uint {
+	limit := uint(intMax) - uint(x) - t + 1
+	y := int(uint(x) + t)
+
+	if sc.strided {
+		shifted, err := sc.set.Clamp(y, intMax).Shift(-int(t))
+		if err != nil {
+			return 0
+		}
+		d := sc.set.Clamp(x, int(uint(intMax)-t)).Xor(shifted)
+		if lo, _, ok := boundsOf(d); ok {
+			return smallestOfUint(limit, uint(lo)-uint(x))
+		}
+		return limit
+	}
+
+	// compare the ranges from x with the ranges from x+t, moved back
+	es := sc.elements
+	i, j := sc.start(x), sc.start(y)
+	for {
+		if i == len(es) && j == len(es) {
+			return limit
+		} else if i == len(es) {
+			return smallestOfUint(limit, uint(largestOf(es[j].first, y))-uint(y))
+		} else if j == len(es) {
+			return smallestOfUint(limit, uint(largestOf(es[i].first, x))-uint(x))
+		}
+
+		alo, ahi := uint(largestOf(es[i].first, x))-uint(x), uint(es[i].last)-uint(x)
+		blo, bhi := uint(largestOf(es[j].first, y))-uint(y), uint(es[j].last)-uint(y)
+		if alo != blo {
+			return smallestOfUint(limit, smallestOfUint(alo, blo))
+		} else if ahi != bhi {
+			return smallestOfUint(limit, smallestOfUint(ahi, bhi)+1)
+		}
+		i, j = i+1, j+1
+	}
+}
+
+// smallestOfUint returns the smaller of a and b.
+func smallestOfUint(a, b uint) uint {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// primeFactors returns the prime factors of n > 0, possibly repeated.
+func primeFactors(n int) []int {
+	var ps []int
+	for p := 2; p < 1<<10 && p <= n/p; p++ {
+		if n%p == 0 {
+			ps = append(ps, p)
+			for n%p == 0 {
+				n /= p
+			}
+		}
+	}
+	if n > 1 {
+		ps = append(ps, largeFactors(uint64(n))...)
+	}
+
+	return ps
+}
+
+// largeFactors returns the prime factors of n, which has no factors
+// below 2^10, by Pollard's rho method.
+func largeFactors(n uint64) []int {
+	if n == 1 {
+		return nil
+	} else if isPrime(n) {
+		return []int{int(n)}
+	}
+
+	for c := uint64(1); ; c++ {
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		for d == 1 {
+			x = (mulMod(x, x, n) + c) % n
+			y = (mulMod(y, y, n) + c) % n
+			y = (mulMod(y, y, n) + c) % n
+			if x > y {
+				d = gcd64(x-y, n)
+			} else {
+				d = gcd64(y-x, n)
+			}
+		}
+		if d != n {
+			return append(largeFactors(d), largeFactors(n/d)...)
+		}
+	}
+}
+
+// isPrime returns true if n is prime, by the Miller-Rabin test with
+// bases sufficient for 64-bit integers.
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	bases := []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+	for _, p := range bases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	d, r := n-1, 0
+	for d%2 == 0 {
+		d, r = d/2, r+1
+	}
+	for _, a := range bases {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for i := 1; i < r && composite; i++ {
+			x = mulMod(x, x, n)
+			composite = x != n-1
+		}
+		if composite {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mulMod returns a*b modulo m, for a and b below m.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, r := bits.Div64(hi, lo, m)
+
+	return r
+}
+
+// powMod returns b^e modulo m.
+func powMod(b, e, m uint64) uint64 {
+	p := uint64(1)
+	for b %= m; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			p = mulMod(p, b, m)
+		}
+		b = mulMod(b, b, m)
+	}
+
+	return p
+}
+
+// gcd64 returns the greatest common divisor of a and b.
+func gcd64(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
@@ -0,0 +1,144 @@
+package intset
+
+// Dilate returns a new set where every range of the set is grown by k
+// integers on both sides, joining ranges which come to overlap or be
+// adjacent. Infinite ends stay infinite, and finite ends are limited
+// to the integers of the platform.
+func (a *IntSet) Dilate(k int) *IntSet {
+	return a.dilate(k, k)
+}
+
+// Erode returns a new set where every range of the set is shrunk by k
+// integers on both sides. Ranges holding 2k integers or less are
+// removed. Infinite ends stay infinite.
+func (a *IntSet) Erode(k int) *IntSet {
+	if k <= 0 {
+		return a.Copy()
+	} else if a.strided() {
+		return a.Complement().dilate(k, k).Complement()
+	}
+
+	n := &IntSet{}
+	for _, e := range a.elements {
+		lo, hi, neg, pos := e.bounds()
+		if !neg {
+			if lo > intMax-k {
+				continue
+			}
+			lo += k
+		}
+		if !pos {
+			if hi < intMin+k {
+				continue
+			}
+			hi -= k
+		}
+		if neg || pos || lo <= hi {
+			n.elements = append(n.elements, fromBounds(lo, hi, neg, pos))
+		}
+	}
+
+	return n
+}
+
+// Opening returns the set eroded and then dilated by k, which removes
+// ranges holding 2k integers or less while keeping the others intact.
+func (a *IntSet) Opening(k int) *IntSet {
+	return a.Erode(k).Dilate(k)
+}
+
+// Closing returns the set dilated and then eroded by k, which fills
+// gaps of 2k integers or less while keeping the outer ends of the
+// ranges intact.
+func (a *IntSet) Closing(k int) *IntSet {
+	return a.Dilate(k).Erode(k)
+}
+
+// CloseGaps returns a new set where every gap of maxGap integers or
+// less between two ranges is filled.
+func (a *IntSet) CloseGaps(maxGap int) *IntSet {
+	if maxGap <= 0 {
+		return a.Copy()
+	} else if a.strided() {
+		return a.Complement().DropShorterThan(maxGap + 1).Complement()
+	}
+
+	n := &IntSet{}
+	for _, e := range a.elements {
+		if len(n.elements) > 0 {
+			p := n.elements[len(n.elements)-1]
+			_, phi, _, _ := p.bounds()
+			lo, _, _, _ := e.bounds()
+			if uint(lo)-uint(phi)-1 <= uint(maxGap) {
+				n.elements[len(n.elements)-1] = p.join(e)
+				continue
+			}
+		}
+		n.elements = append(n.elements, e)
+	}
+
+	return n
+}
+
+// DropShorterThan returns a new set without the ranges holding fewer
+// than n integers. Infinite ranges are always kept.
+func (a *IntSet) DropShorterThan(n int) *IntSet {
+	if n <= 1 {
+		return a.Copy()
+	} else if a.strided() {
+		// keep the integers starting a run of n integers, and
+		// grow them back to the full runs
+		return a.Complement().dilate(n-1, 0).Complement().dilate(0, n-1)
+	}
+
+	r := &IntSet{}
+	for _, e := range a.elements {
+		if c, inf := e.Cardinality(); inf || c >= uint(n) {
+			r.elements = append(r.elements, e)
+		}
+	}
+
+	return r
+}
+
+// dilate returns a new set where every integer x of the set is
+// replaced by the range from x-below to x+above.
+func (a *IntSet) dilate(below, above int) *IntSet {
+	if below < 0 {
+		below = 0
+	}
+	if above < 0 {
+		above = 0
+	}
+
+	n := &IntSet{}
+	for _, e := range a.elements {
+		lo, hi, neg, pos := e.bounds()
+		s := e.step()
+		if s == 1 || below+above+1 >= s {
+			n.insertElement(fromBounds(satAdd(lo, -below), satAdd(hi, above), neg, pos))
+			continue
+		}
+
+		// every integer of the progression grows into a range
+		// shorter than the stride, which is the union of shifted
+		// progressions
+		for j := -below; j <= above; j++ {
+			if x := newStep(satAdd(lo, j), satAdd(hi, j), neg, pos, s, modInt(e.residue()+modInt(j, s), s)); x != nil {
+				n.insertElement(x)
+			}
+		}
+	}
+
+	return n
+}
+
+// satAdd returns a+b limited to the integers of the platform.
+func satAdd(a, b int) int {
+	if c, ok := addInt(a, b); ok {
+		return c
+	} else if b > 0 {
+		return intMax
+	}
+	return intMin
+}
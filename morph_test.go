@@ -0,0 +1,91 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDilate(t *testing.T) {
+	a := New(NegInf(-20), Range(0, 2), Range(6, 8), Int(20), PosInf(40))
+	e := "{-∞:-18, -2:10, 18:22, 38:∞}"
+	if fmt.Sprintf("%s", a.Dilate(2)) != e {
+		t.Fatalf("dilate failed: got %s, expected %s", a.Dilate(2), e)
+	}
+
+	a = New(Int(intMax), Int(intMin))
+	e = fmt.Sprintf("{%d:%d, %d:%d}", intMin, intMin+1, intMax-1, intMax)
+	if fmt.Sprintf("%s", a.Dilate(1)) != e {
+		t.Fatalf("dilate failed: got %s, expected %s", a.Dilate(1), e)
+	}
+}
+
+func TestDilateStep(t *testing.T) {
+	a := New(StepAll(0, 4))
+	e := New(StepAll(2, 4)).Complement()
+	if !a.Dilate(1).Equal(e) {
+		t.Fatalf("dilate failed: got %s, expected %s", a.Dilate(1), e)
+	}
+	e = New(All())
+	if !a.Dilate(2).Equal(e) {
+		t.Fatalf("dilate failed: got %s, expected %s", a.Dilate(2), e)
+	}
+}
+
+func TestErode(t *testing.T) {
+	a := New(NegInf(-20), Range(0, 2), Range(6, 10), PosInf(40))
+	e := "{-∞:-22, 8, 42:∞}"
+	if fmt.Sprintf("%s", a.Erode(2)) != e {
+		t.Fatalf("erode failed: got %s, expected %s", a.Erode(2), e)
+	}
+
+	a = New(StepAll(0, 2), Range(10, 20))
+	e = "{11:19}"
+	if fmt.Sprintf("%s", a.Erode(1)) != e {
+		t.Fatalf("erode failed: got %s, expected %s", a.Erode(1), e)
+	}
+}
+
+func TestOpeningClosing(t *testing.T) {
+	a := New(Range(0, 2), Range(6, 20), Int(22), Range(30, 40))
+	e := "{6:20, 30:40}"
+	if fmt.Sprintf("%s", a.Opening(2)) != e {
+		t.Fatalf("opening failed: got %s, expected %s", a.Opening(2), e)
+	}
+	e = "{0:22, 30:40}"
+	if fmt.Sprintf("%s", a.Closing(2)) != e {
+		t.Fatalf("closing failed: got %s, expected %s", a.Closing(2), e)
+	}
+}
+
+func TestCloseGaps(t *testing.T) {
+	a := New(NegInf(-5), Range(0, 2), Range(6, 20), Int(22), Range(30, 40), PosInf(45))
+	e := "{-∞:22, 30:∞}"
+	if fmt.Sprintf("%s", a.CloseGaps(4)) != e {
+		t.Fatalf("close gaps failed: got %s, expected %s", a.CloseGaps(4), e)
+	}
+
+	a = New(Step(0, 20, 3), Int(40))
+	e = "{0:18, 40}"
+	if fmt.Sprintf("%s", a.CloseGaps(2)) != e {
+		t.Fatalf("close gaps failed: got %s, expected %s", a.CloseGaps(2), e)
+	}
+}
+
+func TestDropShorterThan(t *testing.T) {
+	a := New(NegInf(-5), Range(0, 2), Range(6, 20), Int(22), PosInf(45))
+	e := "{-∞:-5, 6:20, 45:∞}"
+	if fmt.Sprintf("%s", a.DropShorterThan(4)) != e {
+		t.Fatalf("drop shorter than failed: got %s, expected %s", a.DropShorterThan(4), e)
+	}
+
+	a = New(StepAll(0, 10), Range(21, 25))
+	e = "{20:25}"
+	if fmt.Sprintf("%s", a.DropShorterThan(2)) != e {
+		t.Fatalf("drop shorter than failed: got %s, expected %s", a.DropShorterThan(2), e)
+	}
+
+	a = New(Range(intMin, intMax))
+	if d := a.DropShorterThan(2); !d.Equal(a) {
+		t.Fatalf("drop shorter than failed: got %s, expected %s", d, a)
+	}
+}
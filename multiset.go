@@ -0,0 +1,125 @@
+package intset
+
+import "fmt"
+
+// IntMultiSet counts how many times each integer has been added, e.g.
+// how many overlapping reservations cover it. The counts are held as
+// piecewise constant values over the same ranges as used by IntSet.
+type IntMultiSet struct {
+	counts *IntervalMap[uint]
+}
+
+// NewMultiSet returns a new empty multiset.
+func NewMultiSet() *IntMultiSet {
+	return &IntMultiSet{counts: NewIntervalMap(func(a, b uint) bool { return a == b })}
+}
+
+// Add adds the integers of e n times to the multiset. An error is
+// returned for infinite progressions, see IntervalMap.Set, and
+// ErrOverflow if a count would exceed the largest uint, in which case
+// the multiset is left unchanged.
+func (m *IntMultiSet) Add(e *Element, n uint) error {
+	if e.stride > 1 && e.inf() {
+		return fmt.Errorf("intset: can not add infinite progression %s to multiset", e)
+	} else if n == 0 {
+		return nil
+	}
+
+	var update []mapEntry[uint]
+	uncovered := New(e)
+	for r, c := range m.counts.All() {
+		for _, x := range r.intersect(e) {
+			if c > ^uint(0)-n {
+				return ErrOverflow
+			}
+			update = append(update, mapEntry[uint]{elem: x, value: c + n})
+		}
+		uncovered.removeElement(r)
+	}
+	for _, x := range uncovered.elements {
+		update = append(update, mapEntry[uint]{elem: x, value: n})
+	}
+
+	for _, u := range update {
+		m.counts.Set(u.elem, u.value)
+	}
+
+	return nil
+}
+
+// Remove removes the integers of e n times from the multiset. Counts
+// never go below zero, and integers reaching zero are removed. An
+// error is returned for infinite progressions, as for Add.
+func (m *IntMultiSet) Remove(e *Element, n uint) error {
+	if e.stride > 1 && e.inf() {
+		return fmt.Errorf("intset: can not remove infinite progression %s from multiset", e)
+	} else if n == 0 {
+		return nil
+	}
+
+	var update []mapEntry[uint]
+	for r, c := range m.counts.All() {
+		for _, x := range r.intersect(e) {
+			if c > n {
+				update = append(update, mapEntry[uint]{elem: x, value: c - n})
+			} else {
+				update = append(update, mapEntry[uint]{elem: x})
+			}
+		}
+	}
+
+	for _, u := range update {
+		if u.value == 0 {
+			m.counts.Delete(u.elem)
+		} else {
+			m.counts.Set(u.elem, u.value)
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of times x has been added.
+func (m *IntMultiSet) Count(x int) uint {
+	c, _ := m.counts.Get(x)
+	return c
+}
+
+// AtLeast returns the set of integers added k times or more.
+func (m *IntMultiSet) AtLeast(k uint) *IntSet {
+	n := &IntSet{}
+	for r, c := range m.counts.All() {
+		if c >= k {
+			n.insertElement(r)
+		}
+	}
+
+	return n
+}
+
+// Max returns the largest count of the multiset and the set of
+// integers having it.
+func (m *IntMultiSet) Max() (uint, *IntSet) {
+	var most uint
+	for _, c := range m.counts.All() {
+		if c > most {
+			most = c
+		}
+	}
+	if most == 0 {
+		return 0, New()
+	}
+
+	return most, m.AtLeast(most)
+}
+
+// Support returns the set of integers with a count above zero.
+func (m *IntMultiSet) Support() *IntSet {
+	return m.counts.Domain()
+}
+
+// String returns the multiset in a human readable form with the count
+// of every range, in compliance with the fmt.Stringer interface.
+func (m *IntMultiSet) String() string {
+	return m.counts.String()
+}
@@ -0,0 +1,86 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMultiSetAdd(t *testing.T) {
+	m := NewMultiSet()
+	m.Add(Range(0, 10), 1)
+	m.Add(Range(5, 15), 1)
+	m.Add(PosInf(8), 2)
+
+	e := "{0:4: 1, 5:7: 2, 8:10: 4, 11:15: 3, 16:∞: 2}"
+	if m.String() != e {
+		t.Fatalf("multiset add failed: got %s, expected %s", m, e)
+	}
+
+	for x, e := range map[int]uint{-1: 0, 0: 1, 7: 2, 8: 4, 15: 3, 1000: 2} {
+		if c := m.Count(x); c != e {
+			t.Fatalf("multiset count failed: %d gave %d, expected %d", x, c, e)
+		}
+	}
+}
+
+func TestMultiSetAddOverflow(t *testing.T) {
+	m := NewMultiSet()
+	m.Add(Range(0, 10), ^uint(0))
+
+	e := fmt.Sprintf("{0:10: %d}", ^uint(0))
+	if err := m.Add(Range(5, 15), 2); err != ErrOverflow || m.String() != e {
+		t.Fatalf("multiset add overflow failed: got %v, %s, expected %v, %s", err, m, ErrOverflow, e)
+	}
+	if c := m.Count(1); c != ^uint(0) {
+		t.Fatalf("multiset count failed: 1 gave %d, expected %d", c, ^uint(0))
+	}
+}
+
+func TestMultiSetRemove(t *testing.T) {
+	m := NewMultiSet()
+	m.Add(Range(0, 10), 1)
+	m.Add(Range(5, 15), 2)
+	m.Remove(Range(0, 20), 1)
+
+	e := "{5:10: 2, 11:15: 1}"
+	if m.String() != e {
+		t.Fatalf("multiset remove failed: got %s, expected %s", m, e)
+	}
+
+	m.Remove(Step(5, 15, 5), 2)
+	e = "{6:9: 2, 11:14: 1}"
+	if m.String() != e {
+		t.Fatalf("multiset remove failed: got %s, expected %s", m, e)
+	}
+
+	if err := m.Add(StepAll(0, 2), 1); err == nil || m.String() != e {
+		t.Fatalf("multiset add of infinite progression failed: got %v, %s", err, m)
+	}
+	if err := m.Remove(StepPosInf(0, 2), 1); err == nil || m.String() != e {
+		t.Fatalf("multiset remove of infinite progression failed: got %v, %s", err, m)
+	}
+}
+
+func TestMultiSetQueries(t *testing.T) {
+	m := NewMultiSet()
+	m.Add(Range(0, 10), 1)
+	m.Add(Range(5, 15), 1)
+	m.Add(Range(8, 20), 1)
+	m.Add(Int(30), 1)
+
+	e := "{5:15}"
+	if fmt.Sprintf("%s", m.AtLeast(2)) != e {
+		t.Fatalf("multiset at least failed: got %s, expected %s", m.AtLeast(2), e)
+	}
+
+	c, s := m.Max()
+	e = "{8:10}"
+	if c != 3 || fmt.Sprintf("%s", s) != e {
+		t.Fatalf("multiset max failed: got %d %s, expected 3 %s", c, s, e)
+	}
+
+	e = "{0:20, 30}"
+	if fmt.Sprintf("%s", m.Support()) != e {
+		t.Fatalf("multiset support failed: got %s, expected %s", m.Support(), e)
+	}
+}
@@ -0,0 +1,168 @@
+package intset
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParsePages returns a new set from a page selection as entered by a
+// user, e.g. in a print dialog:
+//
+//	1-3, 5, 10-
+//
+// Ranges are written with a hyphen, an en dash or an em dash. Open
+// ranges like 10- and -4 run to ∞ and from -∞, see Resolve. Reversed
+// ranges like 5-3 are turned around. Pages are separated by commas,
+// semicolons or white space, and white space is allowed around the
+// dashes. Pages are numbered from 1. Errors are returned as
+// *SyntaxError, telling where in s the selection is invalid.
+func ParsePages(s string) (*IntSet, error) {
+	n := &IntSet{}
+	b := &Builder{}
+	off := 0
+
+	// token returns the next token and its offset, skipping white
+	// space and separators
+	token := func() (string, int) {
+		for off < len(s) {
+			r, w := utf8.DecodeRuneInString(s[off:])
+			if !unicode.IsSpace(r) && r != ',' && r != ';' {
+				break
+			}
+			off += w
+		}
+
+		start := off
+		if off >= len(s) {
+			return "", start
+		}
+		r, w := utf8.DecodeRuneInString(s[off:])
+		switch {
+		case isDash(r):
+			off += w
+			return "-", start
+		case r >= '0' && r <= '9':
+			for off < len(s) && s[off] >= '0' && s[off] <= '9' {
+				off++
+			}
+		default:
+			off += w
+		}
+
+		return s[start:off], start
+	}
+
+	// peekDash returns true if the next token, which may be preceded
+	// by white space but not separators, is a dash
+	peekDash := func() bool {
+		for i := off; i < len(s); {
+			r, w := utf8.DecodeRuneInString(s[i:])
+			if isDash(r) {
+				return true
+			} else if !unicode.IsSpace(r) {
+				return false
+			}
+			i += w
+		}
+		return false
+	}
+
+	page := func(t string, pos int) (int, error) {
+		if t == "" {
+			return 0, &SyntaxError{Offset: pos, Msg: "missing page number"}
+		} else if t[0] < '0' || t[0] > '9' {
+			return 0, &SyntaxError{Offset: pos, Msg: fmt.Sprintf("%q is not a page number", t)}
+		}
+		p, err := strconv.Atoi(t)
+		if err != nil {
+			return 0, &SyntaxError{Offset: pos, Msg: fmt.Sprintf("page %s is too large", t)}
+		} else if p == 0 {
+			return 0, &SyntaxError{Offset: pos, Msg: "pages are numbered from 1"}
+		}
+		return p, nil
+	}
+
+	for {
+		t, pos := token()
+		if t == "" {
+			break
+		}
+
+		if t == "-" {
+			// -n
+			t, pos = token()
+			last, err := page(t, pos)
+			if err != nil {
+				return nil, err
+			}
+			n.insertElement(NegInf(last))
+			continue
+		}
+
+		first, err := page(t, pos)
+		if err != nil {
+			return nil, err
+		}
+		if !peekDash() {
+			b.Add(first)
+			continue
+		}
+		token()
+
+		// n- or n-m, where the range is open if no page follows
+		// before the next separator
+		save := off
+		t, pos = token()
+		if t == "" || t == "-" || !peekNumber(s, save) {
+			off = save
+			n.insertElement(PosInf(first))
+			continue
+		}
+		last, err := page(t, pos)
+		if err != nil {
+			return nil, err
+		}
+		if r, _ := utf8.DecodeRuneInString(s[off:]); off < len(s) && isDash(r) {
+			return nil, &SyntaxError{Offset: off, Msg: fmt.Sprintf("range %d-%d followed by a dash", first, last)}
+		}
+		b.AddRange(first, last)
+	}
+
+	if len(n.elements) == 0 {
+		return b.Build(), nil
+	}
+
+	return n.Union(b.Build()), nil
+}
+
+// peekNumber returns true if the first rune at or after off which is
+// not white space starts a number.
+func peekNumber(s string, off int) bool {
+	for off < len(s) {
+		r, w := utf8.DecodeRuneInString(s[off:])
+		if !unicode.IsSpace(r) {
+			return r >= '0' && r <= '9'
+		}
+		off += w
+	}
+
+	return false
+}
+
+// isDash returns true if r is a hyphen, an en dash or an em dash.
+func isDash(r rune) bool {
+	return r == '-' || r == '–' || r == '—'
+}
+
+// Resolve returns the set clamped to the pages from 1 to total, e.g.
+// to resolve the open ranges of a page selection returned by
+// ParsePages for a document of total pages.
+func (a *IntSet) Resolve(total int) *IntSet {
+	if total < 1 {
+		return New()
+	}
+
+	return a.Clamp(1, total)
+}
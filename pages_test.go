@@ -0,0 +1,74 @@
+package intset
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParsePages(t *testing.T) {
+	tests := map[string]string{
+		"1-3, 5, 10-":       "{1:3, 5, 10:∞}",
+		"-4":                "{-∞:4}",
+		" 1 – 3 ;7—9 ":      "{1:3, 7:9}",
+		"5-3":               "{3:5}",
+		"1 2 3, 7 - 8":      "{1:3, 7:8}",
+		"10-, 2":            "{2, 10:∞}",
+		"-2, 4, 8 -":        "{-∞:2, 4, 8:∞}",
+		"3,,4":              "{3:4}",
+		"":                  "{∅}",
+		"12-10, 11, -3, 20": "{-∞:3, 10:12, 20}",
+	}
+
+	for s, e := range tests {
+		a, err := ParsePages(s)
+		if err != nil {
+			t.Fatalf("parse pages %q failed: %v", s, err)
+		}
+		if fmt.Sprintf("%s", a) != e {
+			t.Fatalf("parse pages %q failed: got %s, expected %s", s, a, e)
+		}
+	}
+}
+
+func TestParsePagesInvalid(t *testing.T) {
+	tests := map[string]int{
+		"-":                    1,
+		"1-3, x":               5,
+		"0":                    0,
+		"2-0":                  2,
+		"1, 2, three":          6,
+		"99999999999999999999": 0,
+		"1-3, ٣":               5,
+		"1-3-5":                3,
+		"2, 4–6–":              8,
+	}
+
+	for s, e := range tests {
+		_, err := ParsePages(s)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Fatalf("parse pages %q failed: got %v, expected syntax error", s, err)
+		}
+		if se.Offset != e {
+			t.Fatalf("parse pages %q failed: got error at %d (%v), expected at %d", s, se.Offset, err, e)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	a, _ := ParsePages("-2, 5-7, 9-")
+
+	tests := map[int]string{
+		10: "{1:2, 5:7, 9:10}",
+		6:  "{1:2, 5:6}",
+		1:  "{1}",
+		0:  "{∅}",
+	}
+
+	for total, e := range tests {
+		if r := a.Resolve(total); fmt.Sprintf("%s", r) != e {
+			t.Fatalf("resolve %s of %d pages failed: got %s, expected %s", a, total, r, e)
+		}
+	}
+}
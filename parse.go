@@ -0,0 +1,161 @@
+package intset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse returns a new set from its textual form. It accepts the form
+// returned by String, e.g. {-∞:-5, 7, 10:20:2, 30:∞}, as well as
+// lists like 1-5,7 and integers separated by white space or newlines.
+// Elements are written as:
+//
+//	n        a single integer
+//	a:b      a range, also written a-b
+//	a:b:s    every s integer from a to b
+//	-∞:b     a range from -∞, also written -inf:b
+//	a:∞      a range to ∞, also written a:inf
+//	-∞:∞     all integers
+//	sℤ+r     every s integer passing through r, also written sZ+r
+//
+// The enclosing braces are optional, and {∅} is the empty set.
+func Parse(s string) (*IntSet, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("intset: missing closing brace in %q", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+
+	n := &IntSet{}
+	b := &Builder{}
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		if item == "∅" {
+			continue
+		}
+		e, err := ParseElement(item)
+		if err != nil {
+			return nil, err
+		}
+		if e.stride > 1 || e.inf() {
+			n.insertElement(e)
+		} else {
+			b.AddRange(e.first, e.last)
+		}
+	}
+
+	if len(n.elements) == 0 {
+		return b.Build(), nil
+	}
+
+	return n.Union(b.Build()), nil
+}
+
+// ParseElement returns a new element from its textual form, as
+// described for Parse.
+func ParseElement(s string) (*Element, error) {
+	if s == "" {
+		return nil, fmt.Errorf("intset: empty element")
+	} else if i := strings.IndexAny(s, "ℤZ"); i > 0 {
+		return parseResidue(s, i)
+	}
+
+	var parts []string
+	if strings.Contains(s, ":") {
+		parts = strings.Split(s, ":")
+	} else if i := strings.Index(s[1:], "-"); i >= 0 {
+		// a-b, where both a and b may be negative
+		parts = []string{s[:i+1], s[i+2:]}
+	} else {
+		parts = []string{s}
+	}
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("intset: invalid element %q", s)
+	}
+
+	lo, neg, err := parseBound(parts[0], true)
+	if err != nil {
+		return nil, fmt.Errorf("intset: invalid element %q: %v", s, err)
+	}
+	if len(parts) == 1 {
+		if neg {
+			return nil, fmt.Errorf("intset: invalid element %q", s)
+		}
+		return Int(lo), nil
+	}
+
+	hi, pos, err := parseBound(parts[1], false)
+	if err != nil {
+		return nil, fmt.Errorf("intset: invalid element %q: %v", s, err)
+	}
+	if !neg && !pos && hi < lo {
+		lo, hi = hi, lo
+	}
+
+	stride := 1
+	if len(parts) == 3 {
+		if stride, err = strconv.Atoi(parts[2]); err != nil || stride < 1 {
+			return nil, fmt.Errorf("intset: invalid element %q: bad stride", s)
+		}
+		if neg && pos {
+			return nil, fmt.Errorf("intset: invalid element %q: use sℤ+r for progressions without ends", s)
+		}
+	}
+
+	var r int
+	if neg {
+		r = modInt(hi, stride)
+	} else {
+		r = modInt(lo, stride)
+	}
+	return newStep(lo, hi, neg, pos, stride, r), nil
+}
+
+// parseResidue parses progressions written sℤ+r, where the ℤ is found
+// at index i.
+func parseResidue(s string, i int) (*Element, error) {
+	stride, err := strconv.Atoi(s[:i])
+	if err != nil || stride < 1 {
+		return nil, fmt.Errorf("intset: invalid element %q: bad stride", s)
+	}
+
+	rest := strings.TrimLeft(s[i:], "ℤZ")
+	r := 0
+	if rest != "" {
+		if r, err = strconv.Atoi(rest); err != nil {
+			return nil, fmt.Errorf("intset: invalid element %q: bad residue", s)
+		}
+	}
+
+	return StepAll(r, stride), nil
+}
+
+// parseBound parses an integer or an infinity. The lower flag tells
+// whether -∞ or ∞ is allowed. It returns true if the bound is
+// infinite.
+func parseBound(s string, lower bool) (int, bool, error) {
+	switch strings.ToLower(s) {
+	case "-∞", "-inf":
+		if !lower {
+			return 0, false, fmt.Errorf("-%c as upper bound", 0x221e)
+		}
+		return 0, true, nil
+	case "∞", "+∞", "inf", "+inf":
+		if lower {
+			return 0, false, fmt.Errorf("%c as lower bound", 0x221e)
+		}
+		return 0, true, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false, fmt.Errorf("bad integer %q", s)
+	}
+
+	return n, false, nil
+}
@@ -0,0 +1,50 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for in, e := range map[string]string{
+		"{∅}": "{∅}",
+		"":    "{∅}",
+		"{-∞:-5000, -400:-34, 49:420, 500:∞}": "{-∞:-5000, -400:-34, 49:420, 500:∞}",
+		"1-5,7":            "{1:5, 7}",
+		"-5--3, -1-1":      "{-5:-3, -1:1}",
+		"3\n1\n2\n\n10\n":  "{1:3, 10}",
+		"-inf:0 10:inf":    "{-∞:0, 10:∞}",
+		"{-∞:∞}":           "{-∞:∞}",
+		"{1024:2048:4}":    "{1024:2048:4}",
+		"2ℤ+1, 4Z":         "{2ℤ+1, 4ℤ}",
+		"-∞:-10:5, 10:∞:5": "{-∞:-10:5, 10:∞:5}",
+		"9:1":              "{1:9}",
+	} {
+		a, err := Parse(in)
+		if err != nil {
+			t.Fatalf("parse failed: %q: %v", in, err)
+		}
+		if fmt.Sprintf("%s", a) != e {
+			t.Fatalf("parse failed: %q gave %s, expected %s", in, a, e)
+		}
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	a := New(NegInf(-100), Range(-10, 10), Step(20, 40, 5), StepPosInf(100, 3))
+	b, err := Parse(a.String())
+	if err != nil {
+		t.Fatalf("parse failed: %q: %v", a.String(), err)
+	}
+	if !a.Equal(b) {
+		t.Fatalf("parse failed: got %s, expected %s", b, a)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"{1, 2", "a", "1:2:3:4", "∞:5", "5:-∞", "-∞", "1:5:0", "1-", "xℤ"} {
+		if a, err := Parse(in); err == nil {
+			t.Fatalf("parse failed: %q gave %s, expected error", in, a)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package intset
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrConflict is returned when a patch does not apply to a set, i.e.
+// when it removes integers which are not in the set, or adds integers
+// which already are.
+var ErrConflict = errors.New("intset: patch does not apply")
+
+// Patch holds the changes from one set to another as the integers
+// added and the integers removed, which are disjoint. Nil sets hold no
+// changes.
+type Patch struct {
+	Added   *IntSet
+	Removed *IntSet
+}
+
+// Diff returns the patch changing old into new.
+func Diff(old, new *IntSet) Patch {
+	return Patch{Added: new.Difference(old), Removed: old.Difference(new)}
+}
+
+// added returns the integers added by the patch.
+func (p Patch) added() *IntSet {
+	if p.Added == nil {
+		return New()
+	}
+	return p.Added
+}
+
+// removed returns the integers removed by the patch.
+func (p Patch) removed() *IntSet {
+	if p.Removed == nil {
+		return New()
+	}
+	return p.Removed
+}
+
+// IsEmpty returns true if the patch holds no changes.
+func (p Patch) IsEmpty() bool {
+	return len(p.added().elements) == 0 && len(p.removed().elements) == 0
+}
+
+// Apply applies the patch to the set. ErrConflict is returned, and the
+// set is left unchanged, if the patch removes integers not in the set
+// or adds integers already in it, as the set is then not the one the
+// patch was made from.
+func (a *IntSet) Apply(p Patch) error {
+	added, removed := p.added(), p.removed()
+	if len(removed.Difference(a).elements) > 0 || len(a.Intersect(added).elements) > 0 {
+		return ErrConflict
+	}
+
+	n := a.Difference(removed).Union(added)
+	a.elements = n.elements
+	a.notify(added, removed)
+
+	return nil
+}
+
+// Invert returns the patch undoing p.
+func (p Patch) Invert() Patch {
+	return Patch{Added: p.removed().Copy(), Removed: p.added().Copy()}
+}
+
+// Compose returns the patch doing p followed by q. Integers added by
+// one and removed by the other are left out.
+func Compose(p, q Patch) Patch {
+	pa, pr, qa, qr := p.added(), p.removed(), q.added(), q.removed()
+
+	return Patch{
+		Added:   qa.Difference(pr).Union(pa.Difference(qr)),
+		Removed: pr.Difference(qa).Union(qr.Difference(pa)),
+	}
+}
+
+// String returns the patch in a human readable form, e.g.
+// +{1:5} -{10}, in compliance with the fmt.Stringer interface.
+func (p Patch) String() string {
+	return fmt.Sprintf("+%s -%s", p.added(), p.removed())
+}
+
+// patchJSON is the JSON form of a patch.
+type patchJSON struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// MarshalJSON returns the patch as a JSON object holding the elements
+// added and removed in the notation of Parse, e.g.
+// {"added":["1:5","7"],"removed":["10:∞"]}, in compliance with the
+// json.Marshaler interface.
+func (p Patch) MarshalJSON() ([]byte, error) {
+	j := patchJSON{Added: elementStrings(p.added()), Removed: elementStrings(p.removed())}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON sets the patch from its JSON form, in compliance with
+// the json.Unmarshaler interface.
+func (p *Patch) UnmarshalJSON(data []byte) error {
+	var j patchJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	added, err := parseElements(j.Added)
+	if err != nil {
+		return err
+	}
+	removed, err := parseElements(j.Removed)
+	if err != nil {
+		return err
+	}
+	p.Added, p.Removed = added, removed
+
+	return nil
+}
+
+// elementStrings returns the elements of the set in the notation of
+// Parse.
+func elementStrings(a *IntSet) []string {
+	ents := []string{}
+	for _, e := range a.elements {
+		ents = append(ents, e.String())
+	}
+
+	return ents
+}
+
+// parseElements returns a new set of elements in the notation of Parse.
+func parseElements(ents []string) (*IntSet, error) {
+	n := New()
+	for _, s := range ents {
+		e, err := ParseElement(s)
+		if err != nil {
+			return nil, err
+		}
+		n.insertElement(e)
+	}
+
+	return n, nil
+}
+
+// The flags of an element in the binary form of patches.
+const (
+	patchNegInf = 1 << iota
+	patchPosInf
+)
+
+// MarshalBinary returns the patch in a compact binary form, in
+// compliance with the encoding.BinaryMarshaler interface. The form is
+// a version byte followed by the added and the removed elements, each
+// list prefixed by its length. Every element is a byte of flags for
+// infinite ends, its stride, and its bounds as variable length
+// integers, where the lower bound is relative to the previous element.
+func (p Patch) MarshalBinary() ([]byte, error) {
+	buf := []byte{1}
+	for _, s := range []*IntSet{p.added(), p.removed()} {
+		buf = binary.AppendUvarint(buf, uint64(len(s.elements)))
+		prev := 0
+		for _, e := range s.elements {
+			lo, hi, neg, pos := e.bounds()
+			var flags byte
+			if neg {
+				flags |= patchNegInf
+			}
+			if pos {
+				flags |= patchPosInf
+			}
+			buf = append(buf, flags)
+			buf = binary.AppendUvarint(buf, uint64(e.step()))
+
+			switch {
+			case neg && pos:
+				buf = binary.AppendUvarint(buf, uint64(e.residue()))
+			case neg:
+				buf = binary.AppendVarint(buf, int64(uint64(hi)-uint64(prev)))
+				prev = hi
+			case pos:
+				buf = binary.AppendVarint(buf, int64(uint64(lo)-uint64(prev)))
+			default:
+				buf = binary.AppendVarint(buf, int64(uint64(lo)-uint64(prev)))
+				buf = binary.AppendUvarint(buf, uint64(hi)-uint64(lo))
+				prev = hi
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+// UnmarshalBinary sets the patch from its binary form, in compliance
+// with the encoding.BinaryUnmarshaler interface.
+func (p *Patch) UnmarshalBinary(data []byte) error {
+	invalid := errors.New("intset: invalid patch encoding")
+	if len(data) == 0 || data[0] != 1 {
+		return invalid
+	}
+	data = data[1:]
+
+	uvarint := func() (uint64, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, false
+		}
+		data = data[n:]
+		return v, true
+	}
+	varint := func() (uint64, bool) {
+		v, n := binary.Varint(data)
+		if n <= 0 {
+			return 0, false
+		}
+		data = data[n:]
+		return uint64(v), true
+	}
+
+	var sets [2]*IntSet
+	for i := range sets {
+		sets[i] = New()
+		count, ok := uvarint()
+		if !ok || count > uint64(len(data)) {
+			return invalid
+		}
+		prev := uint64(0)
+		for ; count > 0; count-- {
+			if len(data) == 0 {
+				return invalid
+			}
+			flags := data[0]
+			data = data[1:]
+			neg, pos := flags&patchNegInf != 0, flags&patchPosInf != 0
+			stride, ok := uvarint()
+			if !ok || flags > patchNegInf|patchPosInf || stride < 1 || stride > uint64(intMax) {
+				return invalid
+			}
+
+			var lo, hi uint64
+			var ok1, ok2 bool
+			residue := 0
+			switch {
+			case neg && pos:
+				var r uint64
+				r, ok1 = uvarint()
+				ok2 = r < stride
+				residue = int(r)
+			case neg:
+				hi, ok1 = varint()
+				hi += prev
+				prev, ok2 = hi, true
+				residue = modInt(int(hi), int(stride))
+			case pos:
+				lo, ok1 = varint()
+				lo += prev
+				ok2 = true
+				residue = modInt(int(lo), int(stride))
+			default:
+				lo, ok1 = varint()
+				lo += prev
+				hi, ok2 = uvarint()
+				hi += lo
+				ok2 = ok2 && int(hi) >= int(lo)
+				prev = hi
+				residue = modInt(int(lo), int(stride))
+			}
+			if !ok1 || !ok2 {
+				return invalid
+			}
+
+			e := newStep(int(lo), int(hi), neg, pos, int(stride), residue)
+			if e == nil {
+				return invalid
+			}
+			sets[i].insertElement(e)
+		}
+	}
+	if len(data) != 0 {
+		return invalid
+	}
+
+	p.Added, p.Removed = sets[0], sets[1]
+
+	return nil
+}
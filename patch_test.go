@@ -0,0 +1,104 @@
+package intset
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestDiffApply(t *testing.T) {
+	a := New(Range(1, 10), PosInf(100))
+	b := New(Range(5, 12), Int(50), PosInf(200))
+
+	p := Diff(a, b)
+	if s, e := p.String(), "+{11:12, 50} -{1:4, 100:199}"; s != e {
+		t.Fatalf("diff %s %s failed: got %s, expected %s", a, b, s, e)
+	}
+
+	c := a.Copy()
+	if err := c.Apply(p); err != nil || !c.Equal(b) {
+		t.Fatalf("apply %s to %s failed: got %s, %v", p, a, c, err)
+	}
+	if err := c.Apply(p); err != ErrConflict || !c.Equal(b) {
+		t.Fatalf("apply %s twice failed: got %s, %v", p, c, err)
+	}
+	if err := c.Apply(p.Invert()); err != nil || !c.Equal(a) {
+		t.Fatalf("apply inverted %s failed: got %s, %v", p, c, err)
+	}
+	if err := c.Apply(Patch{}); err != nil || !c.Equal(a) || !(Patch{}).IsEmpty() {
+		t.Fatalf("apply empty patch failed: got %s, %v", c, err)
+	}
+
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 500; i++ {
+		base := bases[i%len(bases)]
+		a, b, c := randSet(r, base), randSet(r, base), randSet(r, base)
+		p, q := Diff(a, b), Diff(b, c)
+
+		// a:∞ and a:intMax hold the same integers of the
+		// platform, but are not equal sets, so sets near the
+		// limits are compared by value
+		same := func(x, y *IntSet) bool {
+			if base == 0 {
+				return x.Equal(y)
+			}
+			return len(x.Xor(y).elements) == 0
+		}
+
+		x := a.Copy()
+		if err := x.Apply(p); err != nil || !same(x, b) {
+			t.Fatalf("apply %s to %s failed: got %s, expected %s, %v", p, a, x, b, err)
+		}
+		if err := x.Apply(p.Invert()); err != nil || !same(x, a) {
+			t.Fatalf("apply inverted %s to %s failed: got %s, expected %s, %v", p, b, x, a, err)
+		}
+		if err := x.Apply(Compose(p, q)); err != nil || !same(x, c) {
+			t.Fatalf("apply composed %s, %s to %s failed: got %s, expected %s, %v", p, q, a, x, c, err)
+		}
+	}
+}
+
+func TestPatchEncoding(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 500; i++ {
+		base := bases[i%len(bases)]
+		p := Diff(randSet(r, base), randSet(r, base))
+
+		b, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatalf("marshal binary %s failed: %v", p, err)
+		}
+		var q Patch
+		if err := q.UnmarshalBinary(b); err != nil || !q.added().Equal(p.added()) || !q.removed().Equal(p.removed()) {
+			t.Fatalf("unmarshal binary %s failed: got %s, %v", p, q, err)
+		}
+
+		j, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("marshal json %s failed: %v", p, err)
+		}
+		q = Patch{}
+		if err := json.Unmarshal(j, &q); err != nil || !q.added().Equal(p.added()) || !q.removed().Equal(p.removed()) {
+			t.Fatalf("unmarshal json %s failed: got %s, %v", j, q, err)
+		}
+	}
+
+	p := Diff(New(Range(1000, 1010)), New(Range(1005, 1020), PosInf(5000)))
+	j, _ := json.Marshal(p)
+	if e := `{"added":["1011:1020","5000:∞"],"removed":["1000:1004"]}`; string(j) != e {
+		t.Fatalf("marshal json %s failed: got %s, expected %s", p, j, e)
+	}
+	b, _ := p.MarshalBinary()
+	if len(b) != 17 {
+		t.Fatalf("marshal binary %s failed: got %d bytes %v, expected 17", p, len(b), b)
+	}
+
+	for _, b := range [][]byte{nil, {2}, {1}, {1, 1}, {1, 1, 4, 1, 0}, {1, 1, 0, 0, 2, 0}, {1, 0, 0, 1}} {
+		if err := (&Patch{}).UnmarshalBinary(b); err == nil {
+			t.Fatalf("unmarshal binary %v failed: got no error", b)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"added":["x"]}`), &Patch{}); err == nil {
+		t.Fatalf("unmarshal json failed: got no error")
+	}
+}
@@ -0,0 +1,159 @@
+package intset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxPort is the largest TCP and UDP port number.
+const MaxPort = 65535
+
+// MultiportEntries is the number of entries allowed in an iptables
+// multiport match, where a range counts as two entries.
+const MultiportEntries = 15
+
+// ParsePorts returns a new set of ports from the notations used by
+// firewall rules:
+//
+//	22,80,443,8000:8100   iptables multiport
+//	{ 22, 80-90 }         nftables
+//	1024-65535            a single range
+//
+// Ranges are written a:b or a-b, and iptables style open ranges :b and
+// a: run from port 0 and to MaxPort. Ports outside 0 to MaxPort are
+// errors.
+func ParsePorts(s string) (*IntSet, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("intset: missing closing brace in %q", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+
+	b := &Builder{}
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		lo, hi, open := strings.Cut(item, ":")
+		ranged := open
+		if !ranged {
+			lo, hi, ranged = strings.Cut(item, "-")
+		}
+
+		first, err := parsePort(lo, 0, open)
+		if err != nil {
+			return nil, fmt.Errorf("intset: invalid port %q: %v", item, err)
+		}
+		last := first
+		if ranged {
+			if last, err = parsePort(hi, MaxPort, open); err != nil {
+				return nil, fmt.Errorf("intset: invalid port %q: %v", item, err)
+			} else if last < first {
+				return nil, fmt.Errorf("intset: invalid port %q: reversed range", item)
+			}
+		}
+		b.AddRange(first, last)
+	}
+
+	return b.Build(), nil
+}
+
+// parsePort parses a port number. An empty string returns def if open
+// is true.
+func parsePort(s string, def int, open bool) (int, error) {
+	if s == "" && open {
+		return def, nil
+	} else if s == "" || s[0] < '0' || s[0] > '9' {
+		return 0, fmt.Errorf("bad number %q", s)
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n > MaxPort {
+		return 0, fmt.Errorf("%s is outside 0:%d", s, MaxPort)
+	}
+
+	return n, nil
+}
+
+// PortRanges returns the ranges of the set clamped to the ports from 0
+// to MaxPort, e.g. for security group rules holding a from and to
+// port.
+func PortRanges(a *IntSet) [][2]int {
+	// a clamped set is finite
+	r, _ := a.Clamp(0, MaxPort).ToRanges()
+
+	return r
+}
+
+// FormatMultiport returns the set clamped to the ports from 0 to
+// MaxPort in the notation of iptables multiport, e.g.
+// 22,80,443,8000:8100. The empty string is returned for an empty set.
+// Use SplitPorts to stay within MultiportEntries.
+func FormatMultiport(a *IntSet) string {
+	return formatPorts(a, ":", ",")
+}
+
+// FormatNftables returns the set clamped to the ports from 0 to MaxPort
+// in the notation of nftables, e.g. { 22, 80-90 }. A set of one port
+// or range is written without braces, e.g. 1024-65535. The empty
+// string is returned for an empty set.
+func FormatNftables(a *IntSet) string {
+	s := formatPorts(a, "-", ", ")
+	if len(PortRanges(a)) > 1 {
+		return "{ " + s + " }"
+	}
+
+	return s
+}
+
+// formatPorts returns the ranges of the set clamped to the ports,
+// written with the range and list separators.
+func formatPorts(a *IntSet, rsep, lsep string) string {
+	var ents []string
+	for _, r := range PortRanges(a) {
+		if r[0] == r[1] {
+			ents = append(ents, strconv.Itoa(r[0]))
+		} else {
+			ents = append(ents, fmt.Sprintf("%d%s%d", r[0], rsep, r[1]))
+		}
+	}
+
+	return strings.Join(ents, lsep)
+}
+
+// SplitPorts splits the set clamped to the ports from 0 to MaxPort
+// into sets of at most MultiportEntries entries each, where a range
+// counts as two entries, so that every set fits in one iptables
+// multiport rule. An error is returned if more than maxRules sets are
+// needed. A maxRules of 0 or less sets no limit.
+func SplitPorts(a *IntSet, maxRules int) ([]*IntSet, error) {
+	var chunks []*IntSet
+	var b *Builder
+	entries := 0
+	for _, r := range PortRanges(a) {
+		n := 2
+		if r[0] == r[1] {
+			n = 1
+		}
+		if b == nil || entries+n > MultiportEntries {
+			if b != nil {
+				chunks = append(chunks, b.Build())
+			}
+			b, entries = &Builder{}, 0
+		}
+		b.AddRange(r[0], r[1])
+		entries += n
+	}
+	if b != nil {
+		chunks = append(chunks, b.Build())
+	}
+
+	if maxRules > 0 && len(chunks) > maxRules {
+		return nil, fmt.Errorf("intset: ports need %d rules, more than %d", len(chunks), maxRules)
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,84 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParsePorts(t *testing.T) {
+	tests := map[string]string{
+		"22,80,443,8000:8100": "{22, 80, 443, 8000:8100}",
+		"{ 22, 80-90 }":       "{22, 80:90}",
+		"1024-65535":          "{1024:65535}",
+		":1023":               "{0:1023}",
+		"8080:":               "{8080:65535}",
+		"22 80,81":            "{22, 80:81}",
+		"{}":                  "{∅}",
+		"":                    "{∅}",
+	}
+
+	for s, e := range tests {
+		a, err := ParsePorts(s)
+		if err != nil {
+			t.Fatalf("parse ports %q failed: %v", s, err)
+		}
+		if fmt.Sprintf("%s", a) != e {
+			t.Fatalf("parse ports %q failed: got %s, expected %s", s, a, e)
+		}
+	}
+
+	for _, s := range []string{"65536", "-1", "1-", "-5", "90-80", "http", "{ 22", "22:70000", "+22"} {
+		if _, err := ParsePorts(s); err == nil {
+			t.Fatalf("parse ports %q failed: got no error", s)
+		}
+	}
+}
+
+func TestFormatPorts(t *testing.T) {
+	a := New(Int(22), Range(80, 90), Int(443), PosInf(60000))
+
+	if s, e := FormatMultiport(a), "22,80:90,443,60000:65535"; s != e {
+		t.Fatalf("format multiport %s failed: got %s, expected %s", a, s, e)
+	}
+	if s, e := FormatNftables(a), "{ 22, 80-90, 443, 60000-65535 }"; s != e {
+		t.Fatalf("format nftables %s failed: got %s, expected %s", a, s, e)
+	}
+	if s, e := FormatNftables(New(NegInf(1023)).Complement()), "1024-65535"; s != e {
+		t.Fatalf("format nftables failed: got %s, expected %s", s, e)
+	}
+	if s := FormatMultiport(New(NegInf(-1))); s != "" {
+		t.Fatalf("format multiport of no ports failed: got %q", s)
+	}
+	if r := PortRanges(New(All())); len(r) != 1 || r[0] != [2]int{0, MaxPort} {
+		t.Fatalf("port ranges of all failed: got %v", r)
+	}
+}
+
+func TestSplitPorts(t *testing.T) {
+	a := New()
+	for i := 0; i < 10; i++ {
+		a.AddElements(Range(1000+i*10, 1001+i*10))
+	}
+	a.AddInts(1, 3, 5, 7, 9, 11)
+
+	chunks, err := SplitPorts(a, 0)
+	if err != nil {
+		t.Fatalf("split ports %s failed: %v", a, err)
+	}
+
+	var got []string
+	for _, c := range chunks {
+		got = append(got, FormatMultiport(c))
+	}
+	e := "[1,3,5,7,9,11,1000:1001,1010:1011,1020:1021,1030:1031 1040:1041,1050:1051,1060:1061,1070:1071,1080:1081,1090:1091]"
+	if fmt.Sprintf("%v", got) != e {
+		t.Fatalf("split ports %s failed: got %v, expected %s", a, got, e)
+	}
+
+	if _, err := SplitPorts(a, 1); err == nil {
+		t.Fatalf("split ports %s in 1 rule failed: got no error", a)
+	}
+	if chunks, err := SplitPorts(New(), 1); err != nil || len(chunks) != 0 {
+		t.Fatalf("split ports of empty set failed: got %v, %v", chunks, err)
+	}
+}
@@ -0,0 +1,229 @@
+package intset
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// window is the distance from the base of generated sets within which
+// they are compared with the reference model. It is wide enough to
+// hold a full period of every generated progression on both sides of
+// the elements.
+const window = 200
+
+// bases are the offsets of generated sets, exercising the limits of
+// the integers of the platform.
+var bases = []int{0, intMax - 40, intMin + 40}
+
+// decodeSet returns a set decoded from data, three bytes per element,
+// with its elements near base.
+func decodeSet(data []byte, base int) *IntSet {
+	a := New()
+	for i := 0; i+2 < len(data) && i < 24; i += 3 {
+		lo := satAdd(base, int(int8(data[i+1]))/4)
+		n := int(data[i+2])
+		switch data[i] % 8 {
+		case 0:
+			a.AddElements(Int(lo))
+		case 1:
+			a.AddElements(Range(lo, satAdd(lo, n%10)))
+		case 2:
+			a.AddElements(Step(lo, satAdd(lo, n%30), n%5+1))
+		case 3:
+			a.AddElements(StepPosInf(lo, n%4+1))
+		case 4:
+			a.AddElements(StepNegInf(lo, n%4+1))
+		case 5:
+			a.AddElements(StepAll(lo, n%4+1))
+		case 6:
+			a.AddElements(PosInf(lo))
+		case 7:
+			a.AddElements(NegInf(lo))
+		}
+	}
+
+	return a
+}
+
+// randSet returns a random set with up to four elements near base.
+func randSet(r *rand.Rand, base int) *IntSet {
+	data := make([]byte, 3*r.Intn(5))
+	r.Read(data)
+
+	return decodeSet(data, base)
+}
+
+// model returns the integers of the set within the window around base
+// as a reference model.
+func model(a *IntSet, base int) map[int]bool {
+	m := make(map[int]bool)
+	for i := -window; i <= window; i++ {
+		if n, ok := addInt(base, i); ok && a.HasInt(n) {
+			m[n] = true
+		}
+	}
+
+	return m
+}
+
+// sameModel returns true if the two models are equal.
+func sameModel(x, y map[int]bool) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for n := range x {
+		if !y[n] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkCanonical fails the test if the set is not in canonical form:
+// its elements are valid, disjoint and ordered, progressions hold more
+// than one integer, and ranges are joined when nothing is strided.
+func checkCanonical(t *testing.T, op string, a *IntSet) {
+	t.Helper()
+
+	for i, e := range a.elements {
+		switch {
+		case e.stride == 1:
+			t.Fatalf("%s failed: %s holds element %s with stride 1", op, a, e)
+		case !e.inf() && e.first > e.last:
+			t.Fatalf("%s failed: %s holds reversed element %s", op, a, e)
+		case e.stride > 1 && !e.inf() && e.first == e.last:
+			t.Fatalf("%s failed: %s holds progression %s of one integer", op, a, e)
+		case e.stride > 1 && !e.inf() && (uint(e.last)-uint(e.first))%uint(e.stride) != 0:
+			t.Fatalf("%s failed: %s holds misaligned progression %s", op, a, e)
+		case e.all && e.stride == 0 && len(a.elements) != 1:
+			t.Fatalf("%s failed: %s holds more than all integers", op, a)
+		}
+
+		for _, o := range a.elements[i+1:] {
+			if len(e.intersect(o)) > 0 {
+				t.Fatalf("%s failed: %s holds overlapping elements %s and %s", op, a, e, o)
+			} else if !lessElement(e, o) {
+				t.Fatalf("%s failed: %s holds unordered elements %s and %s", op, a, e, o)
+			}
+		}
+
+		if !a.strided() && i > 0 && a.elements[i-1].isAdjacent(e) {
+			t.Fatalf("%s failed: %s holds adjacent elements %s and %s", op, a, a.elements[i-1], e)
+		}
+	}
+}
+
+func TestPropertyOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for it := 0; it < 1000; it++ {
+		base := bases[it%len(bases)]
+		a, b := randSet(r, base), randSet(r, base)
+		ma, mb := model(a, base), model(b, base)
+		checkCanonical(t, "add", a)
+
+		ops := []struct {
+			name string
+			set  *IntSet
+			has  func(x, y bool) bool
+		}{
+			{"union", a.Union(b), func(x, y bool) bool { return x || y }},
+			{"intersect", a.Intersect(b), func(x, y bool) bool { return x && y }},
+			{"difference", a.Difference(b), func(x, y bool) bool { return x && !y }},
+			{"xor", a.Xor(b), func(x, y bool) bool { return x != y }},
+			{"complement", a.Complement(), func(x, y bool) bool { return !x }},
+		}
+
+		for _, op := range ops {
+			checkCanonical(t, op.name, op.set)
+			m := model(op.set, base)
+			for i := -window; i <= window; i++ {
+				n, ok := addInt(base, i)
+				if ok && m[n] != op.has(ma[n], mb[n]) {
+					t.Fatalf("%s failed: %s, %s gave %s, wrong at %d", op.name, a, b, op.set, n)
+				}
+			}
+		}
+
+		if a.Equal(b) != sameModel(ma, mb) {
+			t.Fatalf("equal failed: %s, %s gave %t", a, b, a.Equal(b))
+		}
+		if a.IsSubsetOf(b) != sameModel(model(a.Intersect(b), base), ma) {
+			t.Fatalf("subset failed: %s, %s gave %t", a, b, a.IsSubsetOf(b))
+		}
+
+		lo, hi := satAdd(base, -window/2), satAdd(base, window/2)
+		w := a.Clamp(lo, hi)
+		checkCanonical(t, "clamp", w)
+		c, inf := w.Cardinality()
+		count := 0
+		for n := range ma {
+			if n >= lo && n <= hi {
+				count++
+			}
+		}
+		if inf || int(c) != count {
+			t.Fatalf("cardinality failed: %s gave %d, expected %d", w, c, count)
+		}
+	}
+}
+
+func TestPropertyLaws(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for it := 0; it < 400; it++ {
+		base := bases[it%len(bases)]
+		a, b, c := randSet(r, base), randSet(r, base), randSet(r, base)
+
+		laws := []struct {
+			name string
+			x, y *IntSet
+		}{
+			{"de morgan union", a.Union(b).Complement(), a.Complement().Intersect(b.Complement())},
+			{"de morgan intersect", a.Intersect(b).Complement(), a.Complement().Union(b.Complement())},
+			{"distributive intersect", a.Intersect(b.Union(c)), a.Intersect(b).Union(a.Intersect(c))},
+			{"distributive union", a.Union(b.Intersect(c)), a.Union(b).Intersect(a.Union(c))},
+			{"idempotent union", a.Union(a), a},
+			{"idempotent intersect", a.Intersect(a), a},
+			{"involution", a.Complement().Complement(), a},
+			{"xor", a.Xor(b), a.Difference(b).Union(b.Difference(a))},
+			{"commutative union", a.Union(b), b.Union(a)},
+			{"associative intersect", a.Intersect(b).Intersect(c), a.Intersect(b.Intersect(c))},
+			{"difference", a.Difference(b), a.Intersect(b.Complement())},
+		}
+
+		for _, law := range laws {
+			// a:∞ and a:intMax hold the same integers of the
+			// platform, but are not equal sets
+			if !sameModel(model(law.x, base), model(law.y, base)) || base == 0 && !law.x.Equal(law.y) {
+				t.Fatalf("%s law failed: %s, %s, %s gave %s and %s", law.name, a, b, c, law.x, law.y)
+			}
+		}
+	}
+}
+
+func TestPropertyMutationDelta(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for it := 0; it < 600; it++ {
+		base := bases[it%len(bases)]
+		a, b := randSet(r, base), randSet(r, base)
+		ma, mb := model(a, base), model(b, base)
+
+		var delta *IntSet
+		name, has := "add", func(x, y bool) bool { return !x && y }
+		if it%2 == 0 {
+			delta = a.Copy().AddElements(b.elements...)
+		} else {
+			name, has = "remove", func(x, y bool) bool { return x && y }
+			delta = a.Copy().RemoveElements(b.elements...)
+		}
+
+		checkCanonical(t, name, delta)
+		m := model(delta, base)
+		for i := -window; i <= window; i++ {
+			n, ok := addInt(base, i)
+			if ok && m[n] != has(ma[n], mb[n]) {
+				t.Fatalf("%s delta failed: %s, %s gave %s, wrong at %d", name, a, b, delta, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package intset
+
+import (
+	"math/rand"
+	"reflect"
+)
+
+// Generate returns a random set of up to size elements, in compliance
+// with the testing/quick Generator interface. The sets hold ranges,
+// progressions and infinite ends near the integers from -size to
+// size.
+func (a *IntSet) Generate(r *rand.Rand, size int) reflect.Value {
+	n := New()
+	if size > 0 {
+		for i := r.Intn(size + 1); i > 0; i-- {
+			n.insertElement(randElement(r, size))
+		}
+	}
+
+	return reflect.ValueOf(n)
+}
+
+// Generate returns a random element near the integers from -size to
+// size, in compliance with the testing/quick Generator interface.
+func (e *Element) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(randElement(r, size))
+}
+
+// randElement returns a random element near the integers from -size
+// to size. Most elements are single integers and ranges, while
+// infinite ends and progressions are less likely.
+func randElement(r *rand.Rand, size int) *Element {
+	if size < 1 {
+		size = 1
+	}
+	lo := r.Intn(2*size+1) - size
+	hi := lo + r.Intn(size+1)
+	stride := 2 + r.Intn(4)
+
+	switch k := r.Intn(20); {
+	case k < 6:
+		return Int(lo)
+	case k < 12:
+		return Range(lo, hi)
+	case k < 14:
+		return NegInf(lo)
+	case k < 16:
+		return PosInf(lo)
+	case k < 17:
+		return StepNegInf(lo, stride)
+	case k < 18:
+		return StepPosInf(lo, stride)
+	case k < 19:
+		if r.Intn(4) == 0 {
+			return All()
+		}
+		return StepAll(lo, stride)
+	}
+
+	return Step(lo, hi, stride)
+}
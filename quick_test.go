@@ -0,0 +1,42 @@
+package intset
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func TestGenerate(t *testing.T) {
+	deMorgan := func(a, b *IntSet) bool {
+		return a.Union(b).Complement().Equal(a.Complement().Intersect(b.Complement()))
+	}
+	if err := quick.Check(deMorgan, nil); err != nil {
+		t.Fatalf("de morgan failed: %v", err)
+	}
+
+	subset := func(e *Element, a *IntSet) bool {
+		b := a.Copy()
+		b.AddElements(e)
+		return New(e).IsSubsetOf(b) && a.IsSubsetOf(b)
+	}
+	if err := quick.Check(subset, nil); err != nil {
+		t.Fatalf("subset failed: %v", err)
+	}
+
+	kinds := map[string]bool{}
+	f := func(e *Element) bool {
+		switch {
+		case e.all:
+			kinds["all"] = true
+		case e.stride > 1:
+			kinds["step"] = true
+		case e.inf():
+			kinds["inf"] = true
+		default:
+			kinds["finite"] = true
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 1000}); err != nil || len(kinds) != 4 {
+		t.Fatalf("generate failed: got kinds %v, %v", kinds, err)
+	}
+}
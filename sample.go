@@ -0,0 +1,165 @@
+package intset
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand"
+	"sort"
+)
+
+// ErrEmpty is returned when sampling from an empty set.
+var ErrEmpty = errors.New("intset: set is empty")
+
+// cumulative returns the cumulative cardinalities of the elements of
+// the set, i.e. the number of integers held by the elements up to and
+// including each element. ErrInfinite is returned for infinite sets,
+// and ErrOverflow if the cardinality can not be held by an unsigned
+// integer.
+func (a *IntSet) cumulative() ([]uint, error) {
+	cum := make([]uint, len(a.elements))
+	var total uint
+	for i, e := range a.elements {
+		if e.inf() {
+			return nil, ErrInfinite
+		}
+		c := e.count()
+		if c == 0 || uintAddOverflow(&total, c) {
+			// a range of every integer of the platform counts 0
+			return nil, ErrOverflow
+		}
+		cum[i] = total
+	}
+
+	return cum, nil
+}
+
+// nth returns the integer of the set at index k in ascending order,
+// given the cumulative cardinalities of the set.
+func (a *IntSet) nth(cum []uint, k uint) int {
+	i := sort.Search(len(cum), func(i int) bool { return cum[i] > k })
+	if i > 0 {
+		k -= cum[i-1]
+	}
+	e := a.elements[i]
+
+	return int(uint(e.first) + k*uint(e.step()))
+}
+
+// randUint returns a uniformly distributed random integer from 0 up
+// to, but not including, n.
+func randUint(r *rand.Rand, n uint) uint {
+	if uint64(n) <= 1<<63-1 {
+		return uint(r.Int63n(int64(n)))
+	}
+
+	// n is at least 2^63, so at least half the draws are kept
+	for {
+		if v := r.Uint64(); v < uint64(n) {
+			return uint(v)
+		}
+	}
+}
+
+// Sample returns an integer of the set drawn at random, where every
+// integer of the set is equally likely. ErrEmpty is returned for the
+// empty set, ErrInfinite for infinite sets, and ErrOverflow if the
+// cardinality of the set can not be held by an unsigned integer.
+func (a *IntSet) Sample(r *rand.Rand) (int, error) {
+	cum, err := a.cumulative()
+	if err != nil {
+		return 0, err
+	} else if len(cum) == 0 {
+		return 0, ErrEmpty
+	}
+
+	return a.nth(cum, randUint(r, cum[len(cum)-1])), nil
+}
+
+// SampleN returns n integers of the set drawn at random, where every
+// integer of the set is equally likely. Without replacement, every
+// integer is drawn at most once, and an error is returned if the set
+// holds fewer than n integers. The errors of Sample are returned as
+// well.
+func (a *IntSet) SampleN(r *rand.Rand, n int, withReplacement bool) ([]int, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("intset: negative sample size %d", n)
+	}
+	cum, err := a.cumulative()
+	if err != nil {
+		return nil, err
+	} else if n == 0 {
+		return []int{}, nil
+	} else if len(cum) == 0 {
+		return nil, ErrEmpty
+	}
+
+	total := cum[len(cum)-1]
+	samples := make([]int, 0, n)
+	if withReplacement {
+		for range n {
+			samples = append(samples, a.nth(cum, randUint(r, total)))
+		}
+		return samples, nil
+	}
+
+	if uint(n) > total {
+		return nil, fmt.Errorf("intset: sample size %d exceeds cardinality %d", n, total)
+	}
+	for m := range a.permutation(r, cum) {
+		if samples = append(samples, m); len(samples) == n {
+			break
+		}
+	}
+
+	return samples, nil
+}
+
+// Permutation returns an iterator over the integers of the set in a
+// random order, where every order is equally likely, visiting every
+// integer exactly once. The set is not expanded, but the iterator
+// keeps track of the integers displaced so far, which are at most as
+// many as the integers visited. The set must not be changed while
+// iterating. The errors of Sample are returned, except for the empty
+// set, which gives an empty iterator.
+func (a *IntSet) Permutation(r *rand.Rand) (iter.Seq[int], error) {
+	cum, err := a.cumulative()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.permutation(r, cum), nil
+}
+
+// permutation returns an iterator over the integers of the set in a
+// random order, given the cumulative cardinalities of the set. It is
+// a Fisher-Yates shuffle of the indexes of the integers, where the
+// indexes swapped are held in a map rather than in an array.
+func (a *IntSet) permutation(r *rand.Rand, cum []uint) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if len(cum) == 0 {
+			return
+		}
+		total := cum[len(cum)-1]
+
+		swapped := make(map[uint]uint)
+		at := func(i uint) uint {
+			if v, ok := swapped[i]; ok {
+				return v
+			}
+			return i
+		}
+
+		for i := uint(0); i < total; i++ {
+			j := i + randUint(r, total-i)
+			k := at(j)
+			if j != i {
+				swapped[j] = at(i)
+			}
+			delete(swapped, i)
+			if !yield(a.nth(cum, k)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package intset
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	a := New(Range(1, 3), Int(100), Step(1000, 1006, 3))
+
+	// every integer is equally likely, not every element
+	counts := make(map[int]int)
+	for range 70000 {
+		n, err := a.Sample(r)
+		if err != nil {
+			t.Fatalf("sample of %s failed: %v", a, err)
+		}
+		counts[n]++
+	}
+	for _, n := range []int{1, 2, 3, 100, 1000, 1003, 1006} {
+		if c := counts[n]; c < 9000 || c > 11000 {
+			t.Fatalf("sample of %s failed: got %d %d times, expected about 10000", a, n, c)
+		}
+	}
+	if len(counts) != 7 {
+		t.Fatalf("sample of %s failed: got %v", a, counts)
+	}
+
+	limits := New(Range(intMax-1, intMax), Int(intMin))
+	for range 100 {
+		if n, err := limits.Sample(r); err != nil || !limits.HasInt(n) {
+			t.Fatalf("sample of %s failed: got %d, %v", limits, n, err)
+		}
+	}
+
+	tests := []struct {
+		a   *IntSet
+		err error
+	}{
+		{New(), ErrEmpty},
+		{New(PosInf(0)), ErrInfinite},
+		{New(StepAll(0, 2)), ErrInfinite},
+		{New(Range(intMin, intMax)), ErrOverflow},
+	}
+	for _, tc := range tests {
+		if _, err := tc.a.Sample(r); err != tc.err {
+			t.Fatalf("sample of %s failed: got %v, expected %v", tc.a, err, tc.err)
+		}
+	}
+}
+
+func TestSampleN(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	a := New(Range(1, 5), Step(10, 20, 5))
+
+	s, err := a.SampleN(r, 50, true)
+	if err != nil || len(s) != 50 {
+		t.Fatalf("sample of 50 from %s failed: got %v, %v", a, s, err)
+	}
+	for _, n := range s {
+		if !a.HasInt(n) {
+			t.Fatalf("sample of 50 from %s failed: got %d", a, n)
+		}
+	}
+
+	s, err = a.SampleN(r, 8, false)
+	if err != nil {
+		t.Fatalf("sample of 8 from %s failed: %v", a, err)
+	}
+	slices.Sort(s)
+	if got := fmt.Sprint(s); got != "[1 2 3 4 5 10 15 20]" {
+		t.Fatalf("sample of 8 from %s failed: got %s", a, got)
+	}
+
+	if _, err := a.SampleN(r, 9, false); err == nil {
+		t.Fatalf("sample of 9 from %s failed: got no error", a)
+	}
+	if _, err := a.SampleN(r, -1, true); err == nil {
+		t.Fatalf("sample of -1 from %s failed: got no error", a)
+	}
+	if s, err := New().SampleN(r, 0, false); err != nil || len(s) != 0 {
+		t.Fatalf("sample of 0 from empty set failed: got %v, %v", s, err)
+	}
+	if _, err := New(NegInf(0)).SampleN(r, 1, false); err != ErrInfinite {
+		t.Fatalf("sample of infinite set failed: got %v", err)
+	}
+
+	// the set is not expanded
+	huge := New(Range(0, intMax/8), Step(intMin/8, -2, 7))
+	s, err = huge.SampleN(r, 1000, false)
+	if err != nil || len(s) != 1000 {
+		t.Fatalf("sample of 1000 from %s failed: got %d, %v", huge, len(s), err)
+	}
+	seen := make(map[int]bool)
+	for _, n := range s {
+		if !huge.HasInt(n) || seen[n] {
+			t.Fatalf("sample of 1000 from %s failed: got %d", huge, n)
+		}
+		seen[n] = true
+	}
+}
+
+func TestPermutation(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	a := New(Range(-3, 3), Step(10, 40, 10), Int(100))
+
+	seq, err := a.Permutation(r)
+	if err != nil {
+		t.Fatalf("permutation of %s failed: %v", a, err)
+	}
+	var got []int
+	for n := range seq {
+		got = append(got, n)
+	}
+	sorted := slices.Sorted(slices.Values(got))
+	if s := fmt.Sprint(sorted); s != "[-3 -2 -1 0 1 2 3 10 20 30 40 100]" {
+		t.Fatalf("permutation of %s failed: got %v", a, got)
+	}
+	if slices.Equal(got, sorted) {
+		t.Fatalf("permutation of %s failed: got ascending order", a)
+	}
+
+	// every order of three integers is equally likely
+	b := New(Int(1), Range(5, 6))
+	orders := make(map[string]int)
+	for range 60000 {
+		seq, _ := b.Permutation(r)
+		var p []int
+		for n := range seq {
+			p = append(p, n)
+		}
+		orders[fmt.Sprint(p)]++
+	}
+	if len(orders) != 6 {
+		t.Fatalf("permutation of %s failed: got %v", b, orders)
+	}
+	for p, c := range orders {
+		if c < 9000 || c > 11000 {
+			t.Fatalf("permutation of %s failed: got %s %d times, expected about 10000", b, p, c)
+		}
+	}
+
+	if seq, err := New().Permutation(r); err != nil {
+		t.Fatalf("permutation of empty set failed: %v", err)
+	} else {
+		for n := range seq {
+			t.Fatalf("permutation of empty set failed: got %d", n)
+		}
+	}
+	if _, err := New(PosInf(0)).Permutation(r); err != ErrInfinite {
+		t.Fatalf("permutation of infinite set failed: got %v", err)
+	}
+}
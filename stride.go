@@ -0,0 +1,705 @@
+package intset
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Step returns an element holding every stride integer from first to
+// last, i.e. the arithmetic progression first, first+stride, ... up
+// to and including last if it is part of the progression. A stride of
+// 1 or less returns an ordinary range.
+func Step(first, last, stride int) *Element {
+	if last < first {
+		first, last = last, first
+	}
+	e := newStep(first, last, false, false, absInt(stride), modInt(first, absInt(stride)))
+	if e == nil {
+		return Int(first)
+	}
+
+	return e
+}
+
+// StepPosInf returns an element holding every stride integer from n
+// to ∞.
+func StepPosInf(n, stride int) *Element {
+	return newStep(n, 0, false, true, absInt(stride), modInt(n, absInt(stride)))
+}
+
+// StepNegInf returns an element holding every stride integer from -∞
+// to n.
+func StepNegInf(n, stride int) *Element {
+	return newStep(0, n, true, false, absInt(stride), modInt(n, absInt(stride)))
+}
+
+// StepAll returns an element holding every stride integer from -∞ to
+// ∞ passing through n, e.g. StepAll(0, 2) holds all even integers.
+func StepAll(n, stride int) *Element {
+	return newStep(0, 0, true, true, absInt(stride), modInt(n, absInt(stride)))
+}
+
+// newStep returns the element holding the integers congruent to
+// residue modulo stride within the bounds. The bounds are aligned to
+// the progression. Nil is returned if no integers are within the
+// bounds.
+func newStep(lo, hi int, neginf, posinf bool, stride, residue int) *Element {
+	if stride <= 1 {
+		if !neginf && !posinf && lo > hi {
+			return nil
+		}
+		return fromBounds(lo, hi, neginf, posinf)
+	}
+
+	var ok bool
+	if !neginf {
+		if lo, ok = alignUp(lo, residue, stride); !ok {
+			return nil
+		}
+	}
+	if !posinf {
+		if hi, ok = alignDown(hi, residue, stride); !ok {
+			return nil
+		}
+	}
+
+	if neginf && posinf {
+		return &Element{all: true, first: residue, stride: stride}
+	} else if neginf {
+		return &Element{neginf: true, first: hi, last: hi, stride: stride}
+	} else if posinf {
+		return &Element{posinf: true, first: lo, last: lo, stride: stride}
+	} else if lo > hi {
+		return nil
+	} else if lo == hi {
+		return Int(lo)
+	}
+
+	return &Element{first: lo, last: hi, stride: stride}
+}
+
+// stepString returns the strided element in a human readable form.
+// Finite progressions are written first:last:stride, and progressions
+// infinite in both directions are written like 4ℤ+1.
+func (e *Element) stepString() string {
+	if e.all {
+		if e.first == 0 {
+			return fmt.Sprintf("%d%c", e.stride, 0x2124)
+		}
+		return fmt.Sprintf("%d%c+%d", e.stride, 0x2124, e.first)
+	} else if e.neginf {
+		return fmt.Sprintf("-%c:%d:%d", 0x221e, e.first, e.stride)
+	} else if e.posinf {
+		return fmt.Sprintf("%d:%c:%d", e.first, 0x221e, e.stride)
+	}
+	return fmt.Sprintf("%d:%d:%d", e.first, e.last, e.stride)
+}
+
+// step returns the distance between the integers of the element.
+func (e *Element) step() int {
+	if e.stride > 1 {
+		return e.stride
+	}
+	return 1
+}
+
+// residue returns the integers of the element modulo the stride.
+func (e *Element) residue() int {
+	if e.stride <= 1 {
+		return 0
+	} else if e.all {
+		return e.first
+	}
+	return modInt(e.first, e.stride)
+}
+
+// has returns true if the integer n is part of the element.
+func (e *Element) has(n int) bool {
+	lo, hi, neg, pos := e.bounds()
+	if (!neg && n < lo) || (!pos && n > hi) {
+		return false
+	}
+
+	return e.stride <= 1 || modInt(n, e.stride) == e.residue()
+}
+
+// count returns the number of integers in a finite element.
+func (e *Element) count() uint {
+	return (uint(e.last)-uint(e.first))/uint(e.step()) + 1
+}
+
+// intersectStep returns the intersection of two elements where at
+// least one is strided. The progressions are intersected by solving
+// the congruences with the Chinese remainder theorem, which gives a
+// progression with a stride of the least common multiple of the two.
+func (e *Element) intersectStep(o *Element) []*Element {
+	var ret []*Element
+
+	es, os := e.step(), o.step()
+	er, or := e.residue(), o.residue()
+
+	elo, ehi, eneg, epos := e.bounds()
+	olo, ohi, oneg, opos := o.bounds()
+	lo, neg := largestOf(elo, olo), eneg && oneg
+	if eneg {
+		lo = olo
+	} else if oneg {
+		lo = elo
+	}
+	hi, pos := smallestOf(ehi, ohi), epos && opos
+	if epos {
+		hi = ohi
+	} else if opos {
+		hi = ehi
+	}
+	if !neg && !pos && lo > hi {
+		return ret
+	}
+
+	r, l, ok := crt(er, es, or, os)
+	if !ok {
+		return ret
+	}
+	if !l.IsInt64() || l.Int64() > int64(intMax) {
+		// The stride does not fit in an int, so at most two
+		// integers of the platform can be part of the
+		// intersection.
+		if neg {
+			lo = intMin
+		}
+		if pos {
+			hi = intMax
+		}
+		x := new(big.Int).SetInt64(int64(lo))
+		d := new(big.Int).Sub(r, x)
+		x.Add(x, d.Mod(d, l))
+		for x.IsInt64() && x.Int64() <= int64(hi) {
+			ret = append(ret, Int(int(x.Int64())))
+			x.Add(x, l)
+		}
+		return ret
+	}
+
+	if n := newStep(lo, hi, neg, pos, int(l.Int64()), int(r.Int64())); n != nil {
+		ret = append(ret, n)
+	}
+
+	return ret
+}
+
+// removeStep returns a list of element sets for removing set o from e
+// where at least one is strided.
+func (e *Element) removeStep(o *Element) []*Element {
+	ret := []*Element{e}
+	for _, x := range e.intersectStep(o) {
+		var next []*Element
+		for _, p := range ret {
+			next = append(next, p.cut(x)...)
+		}
+		ret = next
+	}
+
+	return ret
+}
+
+// cut returns a list of element sets for removing x from e, where x
+// is a progression of integers of e, or a single integer.
+func (e *Element) cut(x *Element) []*Element {
+	var ret []*Element
+
+	if x.stride <= 1 && !x.inf() && x.first == x.last && !e.has(x.first) {
+		ret = append(ret, e)
+		return ret
+	}
+
+	es, er := e.step(), e.residue()
+	elo, ehi, eneg, epos := e.bounds()
+	xlo, xhi, xneg, xpos := x.bounds()
+
+	// the part of e below x
+	if !xneg && xlo != intMin {
+		if n := newStep(elo, xlo-1, eneg, false, es, er); n != nil {
+			ret = append(ret, n)
+		}
+	}
+
+	// the integers of e between the integers of x
+	if xs := x.step(); xs/es > 1 {
+		k := xs / es
+		if !x.inf() && x.count() < uint(k) {
+			// fewer gaps than residue classes
+			for i := xlo; i < xhi; i += xs {
+				if n := newStep(i+1, i+xs-1, false, false, es, er); n != nil {
+					ret = append(ret, n)
+				}
+			}
+		} else {
+			xr := x.residue()
+			for j := 1; j < k; j++ {
+				r := int((uint(xr) + uint(j)*uint(es)) % uint(xs))
+				if n := newStep(xlo, xhi, xneg, xpos, xs, r); n != nil {
+					ret = append(ret, n)
+				}
+			}
+		}
+	}
+
+	// the part of e above x
+	if !xpos && xhi != intMax {
+		if n := newStep(xhi+1, ehi, false, epos, es, er); n != nil {
+			ret = append(ret, n)
+		}
+	}
+
+	return ret
+}
+
+// strided returns true if the set holds strided elements.
+func (a *IntSet) strided() bool {
+	for _, e := range a.elements {
+		if e.stride > 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// insertStep inserts a single element into a set holding strided
+// elements, or inserts a strided element. Overlapping progressions
+// must be split to keep the elements disjoint, so either the new
+// element is cut by the set, or the set is cut by the new element,
+// whichever leaves the fewest elements. The parts of the element which
+// were not already in the set are returned.
+func (a *IntSet) insertStep(r *Element) []*Element {
+	added := []*Element{r}
+	for _, e := range a.elements {
+		added = removeFrom(added, e)
+	}
+	pieces := append(a.elements[:len(a.elements):len(a.elements)], added...)
+
+	cut := []*Element{r}
+	for _, e := range a.elements {
+		cut = append(cut, e.remove(r)...)
+	}
+	if len(cut) < len(pieces) {
+		pieces = cut
+	}
+
+	a.elements = pieces
+	a.normalize()
+
+	return added
+}
+
+// normalize brings a set holding strided elements back to its
+// canonical form. The elements must be disjoint. Progressions with a
+// stride of 1 are collapsed into ranges, progressions together
+// covering all integers of a span are replaced with a range, and
+// progressions continuing each other are joined.
+func (a *IntSet) normalize() {
+	var steps, plain []*Element
+	for _, e := range a.elements {
+		if e.stride > 1 {
+			steps = append(steps, e)
+		} else {
+			plain = append(plain, e)
+		}
+	}
+
+	steps, filled := fillSteps(steps)
+	plain = append(plain, filled...)
+
+	// Move integers between progressions and ranges until nothing
+	// changes. Single integers continuing a progression are joined
+	// with it, while the first and last integers of a progression
+	// adjacent to a range are joined with the range.
+	for changed := true; changed; {
+		changed = false
+
+		n := &IntSet{}
+		for _, e := range plain {
+			n.insertElement(e)
+		}
+		plain = n.elements
+		steps = mergeSteps(steps)
+
+		singles := make(map[int]int)
+		starts := make(map[int]int)
+		ends := make(map[int]int)
+		for i, e := range plain {
+			lo, hi, neg, pos := e.bounds()
+			if !neg && !pos && lo == hi {
+				singles[lo] = i
+			}
+			if !neg {
+				starts[lo] = i
+			}
+			if !pos {
+				ends[hi] = i
+			}
+		}
+
+		// touched marks the ranges changed in this round, which
+		// are left alone until the lookups are rebuilt
+		touched := make([]bool, len(plain))
+		var rest []*Element
+		for _, p := range steps {
+			lo, hi, neg, pos := p.bounds()
+			for !neg && (pos || lo < hi) {
+				if j, ok := singles[lo-p.stride]; ok && lo >= intMin+p.stride && !touched[j] {
+					plain[j], touched[j] = nil, true
+					lo -= p.stride
+				} else if j, ok := ends[lo-1]; ok && lo != intMin && lo <= intMax-p.stride && !touched[j] {
+					plo, _, pneg, _ := plain[j].bounds()
+					plain[j], touched[j] = fromBounds(plo, lo, pneg, false), true
+					lo += p.stride
+				} else {
+					break
+				}
+				changed = true
+			}
+			for !pos && (neg || lo < hi) {
+				if j, ok := singles[hi+p.stride]; ok && hi <= intMax-p.stride && !touched[j] {
+					plain[j], touched[j] = nil, true
+					hi += p.stride
+				} else if j, ok := starts[hi+1]; ok && hi != intMax && hi >= intMin+p.stride && !touched[j] {
+					_, phi, _, ppos := plain[j].bounds()
+					plain[j], touched[j] = fromBounds(hi, phi, false, ppos), true
+					hi -= p.stride
+				} else {
+					break
+				}
+				changed = true
+			}
+			if e := newStep(lo, hi, neg, pos, p.stride, p.residue()); e != nil && e.stride > 1 {
+				rest = append(rest, e)
+			} else if e != nil {
+				plain = append(plain, e)
+			}
+		}
+		steps = rest
+
+		var kept []*Element
+		for _, e := range plain {
+			if e != nil {
+				kept = append(kept, e)
+			}
+		}
+		plain = kept
+	}
+
+	a.elements = append(plain, steps...)
+	sort.Slice(a.elements, func(i, j int) bool {
+		return lessElement(a.elements[i], a.elements[j])
+	})
+}
+
+// maxFillPeriod limits the common period of progressions examined by
+// fillSteps.
+const maxFillPeriod = 1 << 16
+
+// fillSteps replaces the spans where the disjoint progressions cover
+// every integer with ranges. It returns the remaining progressions
+// and the ranges.
+func fillSteps(steps []*Element) ([]*Element, []*Element) {
+	if len(steps) < 2 {
+		return steps, nil
+	}
+
+	p := 1
+	for _, e := range steps {
+		var ok bool
+		if p, ok = lcm(p, e.stride); !ok || p > maxFillPeriod {
+			return steps, nil
+		}
+	}
+
+	var bps []int
+	neg, pos := false, false
+	for _, e := range steps {
+		lo, hi, eneg, epos := e.bounds()
+		if !eneg {
+			bps = append(bps, lo)
+		} else {
+			neg = true
+		}
+		if !epos && hi != intMax {
+			bps = append(bps, hi+1)
+		} else if epos {
+			pos = true
+		}
+	}
+	sort.Ints(bps)
+	var uniq []int
+	for i, b := range bps {
+		if i == 0 || b != bps[i-1] {
+			uniq = append(uniq, b)
+		}
+	}
+	bps = uniq
+
+	// segments holds the spans between the breakpoints
+	type segment struct {
+		lo, hi         int
+		neginf, posinf bool
+	}
+	var segs []segment
+	if neg && len(bps) > 0 && bps[0] != intMin {
+		segs = append(segs, segment{hi: bps[0] - 1, neginf: true})
+	} else if len(bps) == 0 {
+		segs = append(segs, segment{neginf: true, posinf: true})
+	}
+	for i, b := range bps {
+		if i+1 < len(bps) {
+			segs = append(segs, segment{lo: b, hi: bps[i+1] - 1})
+		} else if pos {
+			segs = append(segs, segment{lo: b, posinf: true})
+		} else {
+			segs = append(segs, segment{lo: b, hi: intMax})
+		}
+	}
+
+	// Progressions ending at intMin or intMax hold the same integers
+	// of the platform as progressions running to -∞ or ∞.
+	covers := func(e *Element, s segment) bool {
+		lo, hi, eneg, epos := e.bounds()
+		return (eneg || (s.neginf && lo == intMin) || (!s.neginf && lo <= s.lo)) &&
+			(epos || (s.posinf && hi == intMax) || (!s.posinf && hi >= s.hi))
+	}
+
+	// A segment is filled when the progressions covering it
+	// together hold every residue modulo p.
+	var filled []bool
+	found := false
+	for _, s := range segs {
+		residues := make([]bool, p)
+		count := 0
+		for _, e := range steps {
+			if !covers(e, s) {
+				continue
+			}
+			for r := e.residue(); r < p; r += e.stride {
+				if !residues[r] {
+					residues[r] = true
+					count++
+				}
+			}
+		}
+		filled = append(filled, count == p)
+		found = found || count == p
+	}
+	if !found {
+		return steps, nil
+	}
+
+	var rest, ranges []*Element
+	for i, s := range segs {
+		if filled[i] {
+			ranges = append(ranges, fromBounds(s.lo, s.hi, s.neginf, s.posinf))
+			continue
+		}
+		for _, e := range steps {
+			if !covers(e, s) {
+				continue
+			}
+			lo, hi, eneg, epos := e.bounds()
+			if !s.neginf {
+				lo = s.lo
+			}
+			if !s.posinf {
+				hi = s.hi
+			}
+			if n := newStep(lo, hi, eneg && s.neginf, epos && s.posinf, e.stride, e.residue()); n != nil {
+				rest = append(rest, n)
+			}
+		}
+	}
+
+	// Single integers left over from clipping are plain elements.
+	var steps2 []*Element
+	for _, e := range rest {
+		if e.stride > 1 {
+			steps2 = append(steps2, e)
+		} else {
+			ranges = append(ranges, e)
+		}
+	}
+
+	return steps2, ranges
+}
+
+// mergeSteps joins progressions with equal stride and residue which
+// continue each other.
+func mergeSteps(steps []*Element) []*Element {
+	sort.Slice(steps, func(i, j int) bool {
+		a, b := steps[i], steps[j]
+		if a.stride != b.stride {
+			return a.stride < b.stride
+		} else if a.residue() != b.residue() {
+			return a.residue() < b.residue()
+		}
+		return lessElement(a, b)
+	})
+
+	var ret []*Element
+	for _, e := range steps {
+		if len(ret) > 0 {
+			p := ret[len(ret)-1]
+			plo, phi, pneg, ppos := p.bounds()
+			elo, ehi, eneg, epos := e.bounds()
+			if p.stride == e.stride && p.residue() == e.residue() &&
+				!ppos && !eneg && phi <= intMax-p.stride && phi+p.stride >= elo {
+				if !epos && ehi < phi {
+					ehi = phi
+				}
+				ret[len(ret)-1] = newStep(plo, ehi, pneg, epos, p.stride, p.residue())
+				continue
+			}
+		}
+		ret = append(ret, e)
+	}
+
+	return ret
+}
+
+// lessElement orders elements by their smallest integer, with
+// elements infinite in the negative direction first.
+func lessElement(a, b *Element) bool {
+	alo, _, aneg, _ := a.bounds()
+	blo, _, bneg, _ := b.bounds()
+	if aneg != bneg {
+		return aneg
+	} else if !aneg && alo != blo {
+		return alo < blo
+	} else if a.step() != b.step() {
+		return a.step() < b.step()
+	}
+	return a.residue() < b.residue()
+}
+
+// contiguous returns the set with finite progressions expanded into
+// single integers. An error is returned if the set is infinite.
+func (a *IntSet) contiguous() (*IntSet, error) {
+	for _, e := range a.elements {
+		if e.inf() {
+			return nil, ErrInfinite
+		}
+	}
+
+	return a.expand(), nil
+}
+
+// expand returns the set with finite progressions expanded into
+// single integers. Infinite progressions are kept.
+func (a *IntSet) expand() *IntSet {
+	if !a.strided() {
+		return a
+	}
+
+	b := &Builder{}
+	var inf []*Element
+	for _, e := range a.elements {
+		if e.inf() {
+			inf = append(inf, e)
+			continue
+		}
+		addStrided(b, e.first, e.last, e.stride)
+	}
+	n := b.Build()
+	for _, e := range inf {
+		n.insertElement(e)
+	}
+
+	return n
+}
+
+// crt solves x ≡ r1 (mod m1) and x ≡ r2 (mod m2). It returns the
+// solution modulo the least common multiple of m1 and m2, the least
+// common multiple, and false if there is no solution.
+func crt(r1, m1, r2, m2 int) (*big.Int, *big.Int, bool) {
+	g := gcd(m1, m2)
+	if (r2-r1)%g != 0 {
+		return nil, nil, false
+	}
+
+	bm1, bm2 := big.NewInt(int64(m1)), big.NewInt(int64(m2/g))
+	l := new(big.Int).Mul(bm1, bm2)
+	if m2/g == 1 {
+		return big.NewInt(int64(r1)), l, true
+	}
+
+	// x = r1 + m1·t where t ≡ (r2-r1)/g · (m1/g)⁻¹ (mod m2/g)
+	inv := new(big.Int).ModInverse(big.NewInt(int64(m1/g)), bm2)
+	t := big.NewInt(int64((r2 - r1) / g))
+	t.Mul(t, inv)
+	t.Mod(t, bm2)
+	x := t.Mul(t, bm1)
+	x.Add(x, big.NewInt(int64(r1)))
+
+	return x.Mod(x, l), l, true
+}
+
+// gcd returns the greatest common divisor of two positive integers.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// lcm returns the least common multiple of two positive integers, and
+// false if it overflows.
+func lcm(a, b int) (int, bool) {
+	a = a / gcd(a, b)
+	if a > intMax/b {
+		return 0, false
+	}
+	return a * b, true
+}
+
+// alignUp returns the smallest integer not less than x congruent to r
+// modulo m, and false if it overflows.
+func alignUp(x, r, m int) (int, bool) {
+	d := r - modInt(x, m)
+	if d < 0 {
+		d += m
+	}
+	if x > intMax-d {
+		return 0, false
+	}
+	return x + d, true
+}
+
+// alignDown returns the largest integer not greater than x congruent
+// to r modulo m, and false if it overflows.
+func alignDown(x, r, m int) (int, bool) {
+	d := modInt(x, m) - r
+	if d < 0 {
+		d += m
+	}
+	if x < intMin+d {
+		return 0, false
+	}
+	return x - d, true
+}
+
+// modInt returns n modulo m in the range 0 to m-1.
+func modInt(n, m int) int {
+	if m <= 1 {
+		return 0
+	}
+	r := n % m
+	if r < 0 {
+		r += m
+	}
+	return r
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,151 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStepCompose1(t *testing.T) {
+	a := New(Step(1024, 2050, 4))
+	e := "{1024:2048:4}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("compose failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestStepCompose2(t *testing.T) {
+	a := New(Step(10, 1, 1), Step(20, 20, 3))
+	e := "{1:10, 20}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("compose failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestStepCompose3(t *testing.T) {
+	a := New(StepNegInf(-10, 5), StepPosInf(10, 5), StepAll(1, 3))
+	e := "{3ℤ+1, -∞:-30:15, -∞:-25:15, -15:-10:5, 15:∞:15, 20:∞:15}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("compose failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestStepCollapse(t *testing.T) {
+	a := New(StepAll(0, 2), StepAll(1, 2))
+	e := "{-∞:∞}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("collapse failed: got %s, expected %s", a, e)
+	}
+
+	a = New(Step(0, 10, 2), Step(1, 11, 2))
+	e = "{0:11}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("collapse failed: got %s, expected %s", a, e)
+	}
+
+	a = New(Step(0, 10, 2), Int(12), Int(-2))
+	e = "{-2:12:2}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("collapse failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestStepHasInt(t *testing.T) {
+	a := New(Step(1024, 2048, 4), StepNegInf(-1, 2))
+	for n, e := range map[int]bool{1024: true, 1028: true, 1026: false, 2048: true, 2052: false, -1: true, -3: true, -2: false, 0: false} {
+		if a.HasInt(n) != e {
+			t.Fatalf("has int failed: %d in %s returned %t, expected %t", n, a, !e, e)
+		}
+	}
+}
+
+func TestStepCardinality(t *testing.T) {
+	a := New(Step(1024, 2048, 4), Range(0, 9))
+	var e uint = 267
+	c, inf := a.Cardinality()
+	if inf || c != e {
+		t.Fatalf("cardinality failed: %s, got %d, expected %d", a, c, e)
+	}
+	if _, inf := New(StepAll(0, 2)).Cardinality(); !inf {
+		t.Fatalf("cardinality failed: expected %s to be infinite", New(StepAll(0, 2)))
+	}
+}
+
+func TestStepIntersect(t *testing.T) {
+	a := New(StepAll(0, 4))
+	b := New(StepAll(2, 6))
+	e := "{12ℤ+8}"
+	if fmt.Sprintf("%s", a.Intersect(b)) != e {
+		t.Fatalf("intersection failed: %s %c %s, got %s, expected %s", a, 0x2229, b, a.Intersect(b), e)
+	}
+
+	a = New(StepAll(0, 4))
+	b = New(StepAll(1, 6))
+	e = "{∅}"
+	if fmt.Sprintf("%s", a.Intersect(b)) != e {
+		t.Fatalf("intersection failed: %s %c %s, got %s, expected %s", a, 0x2229, b, a.Intersect(b), e)
+	}
+
+	a = New(Step(1024, 2048, 4))
+	b = New(Range(1030, 1050))
+	e = "{1032:1048:4}"
+	if fmt.Sprintf("%s", a.Intersect(b)) != e {
+		t.Fatalf("intersection failed: %s %c %s, got %s, expected %s", a, 0x2229, b, a.Intersect(b), e)
+	}
+}
+
+func TestStepDifference(t *testing.T) {
+	a := New(Range(0, 20))
+	b := New(StepAll(0, 4))
+	e := "{1:17:4, 2:18:4, 3:19:4}"
+	if fmt.Sprintf("%s", a.Difference(b)) != e {
+		t.Fatalf("difference failed: %s - %s, got %s, expected %s", a, b, a.Difference(b), e)
+	}
+
+	a = New(StepAll(0, 2))
+	b = New(Step(0, 6, 2))
+	e = "{-∞:-2:2, 8:∞:2}"
+	if fmt.Sprintf("%s", a.Difference(b)) != e {
+		t.Fatalf("difference failed: %s - %s, got %s, expected %s", a, b, a.Difference(b), e)
+	}
+}
+
+func TestStepComplement(t *testing.T) {
+	a := New(Step(1024, 2048, 4))
+	e := "{-∞:1023, 1025:2045:4, 1026:2046:4, 1027:2047:4, 2049:∞}"
+	if fmt.Sprintf("%s", a.Complement()) != e {
+		t.Fatalf("complement failed: %s%c, got %s, expected %s", a, 0x2201, a.Complement(), e)
+	}
+	if !a.Complement().Complement().Equal(a) {
+		t.Fatalf("complement failed: %s%c%c, got %s, expected %s", a, 0x2201, 0x2201, a.Complement().Complement(), a)
+	}
+
+	a = New(StepAll(0, 2))
+	e = "{2ℤ+1}"
+	if fmt.Sprintf("%s", a.Complement()) != e {
+		t.Fatalf("complement failed: %s%c, got %s, expected %s", a, 0x2201, a.Complement(), e)
+	}
+}
+
+func TestStepFillLimits(t *testing.T) {
+	a := New(Step(intMax-8, intMax, 4), Step(intMax-7, intMax-3, 4), Step(intMax-6, intMax-2, 4))
+	b := New(Step(intMax-5, intMax-1, 4))
+	e := fmt.Sprintf("{%d:%d}", intMax-8, intMax)
+	if fmt.Sprintf("%s", a.Union(b)) != e {
+		t.Fatalf("union failed: %s %c %s, got %s, expected %s", a, 0x222a, b, a.Union(b), e)
+	}
+
+	a = New(StepPosInf(0, 2), Step(-9, intMax, 2))
+	e = "{-9:-3:2, -1:∞}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("union failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestStepUnion(t *testing.T) {
+	a := New(StepAll(1, 2))
+	b := New(NegInf(21))
+	e := "{-∞:21, 23:∞:2}"
+	if fmt.Sprintf("%s", a.Union(b)) != e {
+		t.Fatalf("union failed: %s %c %s, got %s, expected %s", a, 0x222a, b, a.Union(b), e)
+	}
+}
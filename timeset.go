@@ -0,0 +1,241 @@
+package intset
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TimeSet is a set of instants, e.g. maintenance windows or retention
+// periods, held as an IntSet of time units since the Unix epoch. The
+// granularity is the length of a unit, e.g. time.Second, and should
+// divide or be a multiple of a second. Open ended windows are held as
+// NegInf and PosInf elements, and are given and returned as the zero
+// time.Time.
+type TimeSet struct {
+	set  *IntSet
+	unit time.Duration
+}
+
+// NewTimeSet returns a new empty time set of the granularity. A
+// granularity of 0 or less is one second.
+func NewTimeSet(granularity time.Duration) *TimeSet {
+	if granularity <= 0 {
+		granularity = time.Second
+	}
+
+	return &TimeSet{set: New(), unit: granularity}
+}
+
+// TimeSetOf returns a new time set holding the integers of a as units
+// of the granularity since the Unix epoch. An error is returned if a
+// holds infinite progressions, as they are not windows.
+func TimeSetOf(a *IntSet, granularity time.Duration) (*TimeSet, error) {
+	for _, e := range a.elements {
+		if e.stride > 1 && e.inf() {
+			return nil, fmt.Errorf("intset: progression %s is not a window", e)
+		}
+	}
+
+	t := NewTimeSet(granularity)
+	t.set = a.expand().Copy()
+
+	return t, nil
+}
+
+// IntSet returns the units of the set.
+func (t *TimeSet) IntSet() *IntSet {
+	return t.set.Copy()
+}
+
+// Granularity returns the length of the units of the set.
+func (t *TimeSet) Granularity() time.Duration {
+	return t.unit
+}
+
+// Add adds the window from start up to, but not including, end to the
+// set. Every unit touched by the window is added. A zero start or end
+// is open ended.
+func (t *TimeSet) Add(start, end time.Time) {
+	if e := t.window(start, end); e != nil {
+		t.set.insertElement(e)
+	}
+}
+
+// Remove removes the window from start up to, but not including, end
+// from the set. Every unit touched by the window is removed. A zero
+// start or end is open ended.
+func (t *TimeSet) Remove(start, end time.Time) {
+	if e := t.window(start, end); e != nil {
+		t.set.removeElement(e)
+	}
+}
+
+// window returns the element of the units touched by the window, and
+// nil if the window is empty.
+func (t *TimeSet) window(start, end time.Time) *Element {
+	neg, pos := start.IsZero(), end.IsZero()
+	if !neg && !pos && !start.Before(end) {
+		return nil
+	}
+
+	var lo, hi int
+	if !neg {
+		lo = t.toUnit(start)
+	}
+	if !pos {
+		// the unit holding the last instant before end
+		hi = t.toUnit(end.Add(-1))
+	}
+
+	return fromBounds(lo, hi, neg, pos)
+}
+
+// toUnit returns the unit holding the instant.
+func (t *TimeSet) toUnit(x time.Time) int {
+	sec, nsec := x.Unix(), int64(x.Nanosecond())
+	if t.unit%time.Second == 0 {
+		return int(floorDiv(sec, int64(t.unit/time.Second)))
+	} else if time.Second%t.unit == 0 {
+		return int(sec*int64(time.Second/t.unit) + nsec/int64(t.unit))
+	}
+
+	return int(floorDiv(x.UnixNano(), int64(t.unit)))
+}
+
+// fromUnit returns the first instant of the unit.
+func (t *TimeSet) fromUnit(n int) time.Time {
+	if t.unit%time.Second == 0 {
+		return time.Unix(int64(n)*int64(t.unit/time.Second), 0).UTC()
+	} else if time.Second%t.unit == 0 {
+		k := int64(time.Second / t.unit)
+		return time.Unix(floorDiv(int64(n), k), (int64(n)-floorDiv(int64(n), k)*k)*int64(t.unit)).UTC()
+	}
+
+	return time.Unix(0, int64(n)*int64(t.unit)).UTC()
+}
+
+// floorDiv returns a/b rounded towards -∞.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+
+	return q
+}
+
+// Contains returns true if the instant is part of the set.
+func (t *TimeSet) Contains(x time.Time) bool {
+	return t.set.HasInt(t.toUnit(x))
+}
+
+// Duration returns the total length of the windows of the set and an
+// infinite boolean. If the infinite boolean is true, the set is open
+// ended or its length can not be held by a time.Duration, and the
+// duration must be discarded.
+func (t *TimeSet) Duration() (time.Duration, bool) {
+	c, inf := t.set.Cardinality()
+	if inf || uint64(c) > math.MaxInt64/uint64(t.unit) {
+		return 0, true
+	}
+
+	return time.Duration(c) * t.unit, false
+}
+
+// Windows returns an iterator over the windows of the set in ascending
+// order, as the start and the end of every window. The end is the
+// first instant after the window. Open ends are the zero time.Time.
+func (t *TimeSet) Windows() iter.Seq2[time.Time, time.Time] {
+	return func(yield func(time.Time, time.Time) bool) {
+		for _, e := range t.set.elements {
+			lo, hi, neg, pos := e.bounds()
+			var start, end time.Time
+			if !neg {
+				start = t.fromUnit(lo)
+			}
+			if !pos {
+				end = t.fromUnit(hi).Add(t.unit)
+			}
+			if !yield(start, end) {
+				return
+			}
+		}
+	}
+}
+
+// String returns the windows of the set as RFC 3339 intervals written
+// start/end, with .. for open ends, in compliance with the
+// fmt.Stringer interface.
+func (t *TimeSet) String() string {
+	if len(t.set.elements) == 0 {
+		return fmt.Sprintf("{%c}", 0x2205)
+	}
+
+	layout := time.RFC3339
+	if t.unit%time.Second != 0 {
+		layout = time.RFC3339Nano
+	}
+	format := func(x time.Time) string {
+		if x.IsZero() {
+			return ".."
+		}
+		return x.Format(layout)
+	}
+
+	var ents []string
+	for start, end := range t.Windows() {
+		ents = append(ents, format(start)+"/"+format(end))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(ents, ", "))
+}
+
+// ParseTimeSet returns a new time set of the granularity from RFC 3339
+// intervals written start/end and separated by commas or white space,
+// as returned by String. An open start or end is written .. or left
+// empty. The enclosing braces are optional.
+func ParseTimeSet(s string, granularity time.Duration) (*TimeSet, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") {
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("intset: missing closing brace in %q", s)
+		}
+		s = s[1 : len(s)-1]
+	}
+
+	t := NewTimeSet(granularity)
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		if item == "∅" {
+			continue
+		}
+		from, to, ok := strings.Cut(item, "/")
+		if !ok {
+			return nil, fmt.Errorf("intset: invalid interval %q: missing /", item)
+		}
+
+		var start, end time.Time
+		var err error
+		if from != ".." && from != "" {
+			if start, err = time.Parse(time.RFC3339Nano, from); err != nil {
+				return nil, fmt.Errorf("intset: invalid interval %q: %v", item, err)
+			}
+		}
+		if to != ".." && to != "" {
+			if end, err = time.Parse(time.RFC3339Nano, to); err != nil {
+				return nil, fmt.Errorf("intset: invalid interval %q: %v", item, err)
+			}
+		}
+		if !start.IsZero() && !end.IsZero() && end.Before(start) {
+			return nil, fmt.Errorf("intset: invalid interval %q: end before start", item)
+		}
+		t.Add(start, end)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,110 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeSet(t *testing.T) {
+	day := 24 * time.Hour
+	d := func(s string) time.Time {
+		x, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return x
+	}
+
+	a := NewTimeSet(time.Second)
+	a.Add(d("2024-03-01T02:00:00Z"), d("2024-03-01T04:00:00Z"))
+	a.Add(d("2024-03-01T04:00:00Z"), d("2024-03-01T05:00:00Z"))
+	a.Add(d("2024-03-08T02:00:00Z"), d("2024-03-08T02:30:00Z"))
+
+	e := "{2024-03-01T02:00:00Z/2024-03-01T05:00:00Z, 2024-03-08T02:00:00Z/2024-03-08T02:30:00Z}"
+	if a.String() != e {
+		t.Fatalf("time set failed: got %s, expected %s", a, e)
+	}
+	if dur, inf := a.Duration(); inf || dur != 3*time.Hour+30*time.Minute {
+		t.Fatalf("duration of %s failed: got %s", a, dur)
+	}
+	if !a.Contains(d("2024-03-01T04:59:59Z")) || a.Contains(d("2024-03-01T05:00:00Z")) || a.Contains(d("2024-03-01T01:59:59Z")) {
+		t.Fatalf("contains failed for %s", a)
+	}
+
+	a.Remove(d("2024-03-01T03:00:00Z"), time.Time{})
+	a.Add(time.Time{}, d("1970-01-01T00:00:10Z"))
+	e = "{../1970-01-01T00:00:10Z, 2024-03-01T02:00:00Z/2024-03-01T03:00:00Z}"
+	if a.String() != e {
+		t.Fatalf("time set failed: got %s, expected %s", a, e)
+	}
+	if _, inf := a.Duration(); !inf {
+		t.Fatalf("duration of %s failed: got finite duration", a)
+	}
+	if fmt.Sprintf("%s", a.IntSet()) != "{-∞:9, 1709258400:1709261999}" {
+		t.Fatalf("int set of %s failed: got %s", a, a.IntSet())
+	}
+
+	// windows are widened to the units they touch
+	b := NewTimeSet(day)
+	b.Add(d("2024-02-28T22:00:00Z"), d("2024-03-01T00:00:00Z"))
+	b.Add(d("1969-12-31T12:00:00Z"), d("1970-01-01T12:00:00Z"))
+	e = "{1969-12-31T00:00:00Z/1970-01-02T00:00:00Z, 2024-02-28T00:00:00Z/2024-03-01T00:00:00Z}"
+	if b.String() != e {
+		t.Fatalf("time set failed: got %s, expected %s", b, e)
+	}
+	if dur, _ := b.Duration(); dur != 4*day {
+		t.Fatalf("duration of %s failed: got %s", b, dur)
+	}
+
+	var starts []string
+	for start, end := range b.Windows() {
+		starts = append(starts, start.Format(time.DateOnly)+"/"+end.Format(time.DateOnly))
+	}
+	if fmt.Sprint(starts) != "[1969-12-31/1970-01-02 2024-02-28/2024-03-01]" {
+		t.Fatalf("windows of %s failed: got %v", b, starts)
+	}
+}
+
+func TestTimeSetMilliseconds(t *testing.T) {
+	a := NewTimeSet(time.Millisecond)
+	start := time.Unix(-1, 500*int64(time.Millisecond))
+	a.Add(start, start.Add(1500*time.Microsecond))
+
+	e := "{1969-12-31T23:59:59.5Z/1969-12-31T23:59:59.502Z}"
+	if a.String() != e {
+		t.Fatalf("time set failed: got %s, expected %s", a, e)
+	}
+	if fmt.Sprintf("%s", a.IntSet()) != "{-500:-499}" {
+		t.Fatalf("int set of %s failed: got %s", a, a.IntSet())
+	}
+}
+
+func TestParseTimeSet(t *testing.T) {
+	s := "{../2024-01-01T00:00:00Z, 2024-06-01T00:00:00+02:00/2024-06-02T00:00:00+02:00 2025-01-01T00:00:00Z/}"
+	a, err := ParseTimeSet(s, time.Hour)
+	if err != nil {
+		t.Fatalf("parse time set failed: %v", err)
+	}
+
+	e := "{../2024-01-01T00:00:00Z, 2024-05-31T22:00:00Z/2024-06-01T22:00:00Z, 2025-01-01T00:00:00Z/..}"
+	if a.String() != e {
+		t.Fatalf("parse time set failed: got %s, expected %s", a, e)
+	}
+	if b, err := ParseTimeSet(a.String(), time.Hour); err != nil || b.String() != e {
+		t.Fatalf("parse of string %s failed: got %s, %v", e, b, err)
+	}
+
+	for _, s := range []string{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z/2024-01-01T00:00:00Z", "yesterday/today", "{../.."} {
+		if _, err := ParseTimeSet(s, time.Second); err == nil {
+			t.Fatalf("parse time set %q failed: got no error", s)
+		}
+	}
+
+	if _, err := TimeSetOf(New(StepPosInf(0, 2)), time.Second); err == nil {
+		t.Fatalf("time set of infinite progression failed: got no error")
+	}
+	if b, err := TimeSetOf(New(Step(0, 4, 2)), time.Second); err != nil || b.String() != "{1970-01-01T00:00:00Z/1970-01-01T00:00:01Z, 1970-01-01T00:00:02Z/1970-01-01T00:00:03Z, 1970-01-01T00:00:04Z/1970-01-01T00:00:05Z}" {
+		t.Fatalf("time set of finite progression failed: got %s, %v", b, err)
+	}
+}
@@ -0,0 +1,210 @@
+package intset
+
+import (
+	"errors"
+)
+
+// ErrOverflow is returned when a transformation moves integers of a
+// set beyond the limits of the platform int type.
+var ErrOverflow = errors.New("intset: integer overflow")
+
+// Shift returns a new set with every integer of the set moved by k.
+// Infinite ends stay infinite. An error is returned if an integer
+// overflows.
+func (a *IntSet) Shift(k int) (*IntSet, error) {
+	n := &IntSet{}
+	for _, e := range a.elements {
+		lo, hi, neg, pos := e.bounds()
+
+		var ok bool
+		if !neg {
+			if lo, ok = addInt(lo, k); !ok {
+				return nil, ErrOverflow
+			}
+		}
+		if !pos {
+			if hi, ok = addInt(hi, k); !ok {
+				return nil, ErrOverflow
+			}
+		}
+		s := e.step()
+		n.elements = append(n.elements, newStep(lo, hi, neg, pos, s, modInt(e.residue()+modInt(k, s), s)))
+	}
+	if n.strided() {
+		n.normalize()
+	}
+
+	return n, nil
+}
+
+// Negate returns a new set with every integer x of the set replaced
+// by -x. An error is returned if the set holds the smallest integer
+// of the platform, which can not be negated.
+func (a *IntSet) Negate() (*IntSet, error) {
+	n := &IntSet{}
+	for i := len(a.elements) - 1; i >= 0; i-- {
+		e := a.elements[i]
+		lo, hi, neg, pos := e.bounds()
+		if (!neg && lo == intMin) || (!pos && hi == intMin) {
+			return nil, ErrOverflow
+		}
+		s := e.step()
+		n.elements = append(n.elements, newStep(-hi, -lo, pos, neg, s, modInt(-e.residue(), s)))
+	}
+	if n.strided() {
+		n.normalize()
+	}
+
+	return n, nil
+}
+
+// Scale returns a new set with every integer x of the set replaced
+// by x*k. Ranges become progressions with a stride of |k|, and a
+// negative k turns the set around. Scaling a non-empty set by 0 gives
+// {0}. An error is returned if an integer or a stride overflows.
+func (a *IntSet) Scale(k int) (*IntSet, error) {
+	if k == 0 {
+		if len(a.elements) == 0 {
+			return New(), nil
+		}
+		return New(Int(0)), nil
+	}
+
+	n := &IntSet{}
+	for i := range a.elements {
+		e := a.elements[i]
+		if k < 0 {
+			e = a.elements[len(a.elements)-1-i]
+		}
+		lo, hi, neg, pos := e.bounds()
+
+		var ok bool
+		if !neg {
+			if lo, ok = mulInt(lo, k); !ok {
+				return nil, ErrOverflow
+			}
+		}
+		if !pos {
+			if hi, ok = mulInt(hi, k); !ok {
+				return nil, ErrOverflow
+			}
+		}
+		if k < 0 {
+			lo, hi, neg, pos = hi, lo, pos, neg
+		}
+		if !neg && !pos && lo == hi {
+			n.elements = append(n.elements, Int(lo))
+			continue
+		}
+
+		s, ok := mulInt(e.step(), absInt(k))
+		if !ok || s <= 0 {
+			return nil, ErrOverflow
+		}
+		r := e.residue() * k
+		if !neg {
+			r = lo
+		} else if !pos {
+			r = hi
+		}
+		n.elements = append(n.elements, newStep(lo, hi, neg, pos, s, modInt(r, s)))
+	}
+	if n.strided() {
+		n.normalize()
+	}
+
+	return n, nil
+}
+
+// Clamp returns a new set holding the integers of the set from lo to
+// hi. It is the same as intersecting with Range(lo, hi), but without
+// building a second set.
+func (a *IntSet) Clamp(lo, hi int) *IntSet {
+	r := Range(lo, hi)
+
+	n := &IntSet{}
+	for _, e := range a.elements {
+		n.elements = append(n.elements, e.intersect(r)...)
+	}
+	if n.strided() {
+		n.normalize()
+	}
+
+	return n
+}
+
+// Map returns a new set where every range of the set is replaced by
+// the range between f applied to its first and last integer. The
+// function f must be monotone, i.e. either never decreasing or never
+// increasing. An infinite end is mapped to the infinite end in the
+// direction of f. Finite progressions are mapped integer by integer.
+// An error is returned if f is found not to be monotone, or if the
+// set holds infinite progressions.
+func (a *IntSet) Map(f func(int) int) (*IntSet, error) {
+	n := &IntSet{}
+	for _, e := range a.elements {
+		if e.stride > 1 {
+			if e.inf() {
+				return nil, ErrInfinite
+			}
+			for i, c := e.first, e.count(); c > 0; i, c = i+e.stride, c-1 {
+				n.insertElement(Int(f(i)))
+			}
+			continue
+		}
+
+		lo, hi, neg, pos := e.bounds()
+		if neg && pos {
+			return New(All()), nil
+		} else if neg {
+			// probe the direction next to the finite end
+			if hi == intMin || f(hi-1) <= f(hi) {
+				n.insertElement(NegInf(f(hi)))
+			} else {
+				n.insertElement(PosInf(f(hi)))
+			}
+			continue
+		} else if pos {
+			if lo == intMax || f(lo) <= f(lo+1) {
+				n.insertElement(PosInf(f(lo)))
+			} else {
+				n.insertElement(NegInf(f(lo)))
+			}
+			continue
+		}
+
+		flo, fhi := f(lo), f(hi)
+		if lo < hi && hi-lo > 1 {
+			// sample the middle to catch functions which are not
+			// monotone
+			mid := lo + (hi-lo)/2
+			if fm := f(mid); (fm < flo && fm < fhi) || (fm > flo && fm > fhi) {
+				return nil, errors.New("intset: map function is not monotone")
+			}
+		}
+		n.insertElement(Range(flo, fhi))
+	}
+
+	return n, nil
+}
+
+// addInt returns a+b, and false if the sum overflows.
+func addInt(a, b int) (int, bool) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return 0, false
+	}
+	return c, true
+}
+
+// mulInt returns a*b, and false if the product overflows.
+func mulInt(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == intMin) || (b == -1 && a == intMin) {
+		return 0, false
+	}
+	return c, true
+}
@@ -0,0 +1,146 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShift(t *testing.T) {
+	a := New(NegInf(-10), Range(0, 5), Step(10, 20, 5))
+	b, err := a.Shift(3)
+	if err != nil {
+		t.Fatalf("shift failed: %v", err)
+	}
+	e := "{-∞:-7, 3:8, 13:23:5}"
+	if fmt.Sprintf("%s", b) != e {
+		t.Fatalf("shift failed: %s shifted by 3, got %s, expected %s", a, b, e)
+	}
+
+	b, err = New(StepAll(1, 4)).Shift(-2)
+	if err != nil {
+		t.Fatalf("shift failed: %v", err)
+	}
+	e = "{4ℤ+3}"
+	if fmt.Sprintf("%s", b) != e {
+		t.Fatalf("shift failed: got %s, expected %s", b, e)
+	}
+
+	if _, err := New(Int(intMax - 1)).Shift(2); err != ErrOverflow {
+		t.Fatalf("shift failed: got %v, expected %v", err, ErrOverflow)
+	}
+	if _, err := New(PosInf(intMax - 1)).Shift(2); err != ErrOverflow {
+		t.Fatalf("shift failed: got %v, expected %v", err, ErrOverflow)
+	}
+	if _, err := New(NegInf(intMax - 1)).Shift(-2); err != nil {
+		t.Fatalf("shift failed: %v", err)
+	}
+}
+
+func TestScale(t *testing.T) {
+	a := New(NegInf(-10), Range(0, 3), Int(7), Step(10, 20, 5))
+	tests := []struct {
+		k        int
+		expected string
+	}{
+		{1, "{-∞:-10, 0:3, 7, 10:20:5}"},
+		{2, "{-∞:-20:2, 0:6:2, 14, 20:40:10}"},
+		{-3, "{-60:-30:15, -21, -9:0:3, 30:∞:3}"},
+		{0, "{0}"},
+	}
+
+	for _, tc := range tests {
+		b, err := a.Scale(tc.k)
+		if err != nil {
+			t.Fatalf("scale failed: %v", err)
+		}
+		if fmt.Sprintf("%s", b) != tc.expected {
+			t.Fatalf("scale failed: %s scaled by %d, got %s, expected %s", a, tc.k, b, tc.expected)
+		}
+	}
+
+	b, err := New(StepAll(1, 4), PosInf(100)).Scale(-2)
+	if err != nil {
+		t.Fatalf("scale failed: %v", err)
+	}
+	if e := New(StepAll(6, 8), StepNegInf(-200, 2)); !b.Equal(e) {
+		t.Fatalf("scale failed: got %s, expected %s", b, e)
+	}
+
+	if b, err := New().Scale(0); err != nil || !b.Equal(New()) {
+		t.Fatalf("scale failed: got %s, %v, expected empty set", b, err)
+	}
+	if _, err := New(Int(intMax/2 + 1)).Scale(2); err != ErrOverflow {
+		t.Fatalf("scale failed: got %v, expected %v", err, ErrOverflow)
+	}
+	if _, err := New(Range(-1, 1)).Scale(intMin); err != ErrOverflow {
+		t.Fatalf("scale failed: got %v, expected %v", err, ErrOverflow)
+	}
+	if _, err := New(StepAll(0, intMax/2)).Scale(3); err != ErrOverflow {
+		t.Fatalf("scale failed: got %v, expected %v", err, ErrOverflow)
+	}
+	if b, err := New(Int(1)).Scale(intMin); err != nil || !b.Equal(New(Int(intMin))) {
+		t.Fatalf("scale failed: got %s, %v, expected {%d}", b, err, intMin)
+	}
+}
+
+func TestNegate(t *testing.T) {
+	a := New(NegInf(-10), Range(0, 5), PosInf(20))
+	b, err := a.Negate()
+	if err != nil {
+		t.Fatalf("negate failed: %v", err)
+	}
+	e := "{-∞:-20, -5:0, 10:∞}"
+	if fmt.Sprintf("%s", b) != e {
+		t.Fatalf("negate failed: -%s, got %s, expected %s", a, b, e)
+	}
+
+	b, err = New(StepPosInf(1, 3)).Negate()
+	if err != nil {
+		t.Fatalf("negate failed: %v", err)
+	}
+	e = "{-∞:-1:3}"
+	if fmt.Sprintf("%s", b) != e {
+		t.Fatalf("negate failed: got %s, expected %s", b, e)
+	}
+
+	if _, err := New(Int(intMin)).Negate(); err != ErrOverflow {
+		t.Fatalf("negate failed: got %v, expected %v", err, ErrOverflow)
+	}
+}
+
+func TestClamp(t *testing.T) {
+	a := New(NegInf(-10), Range(0, 5), Step(8, 30, 2), PosInf(40))
+	e := "{-15:-10, 0:5, 8:30:2, 40:50}"
+	if fmt.Sprintf("%s", a.Clamp(-15, 50)) != e {
+		t.Fatalf("clamp failed: got %s, expected %s", a.Clamp(-15, 50), e)
+	}
+	e = "{3:5, 8:10:2}"
+	if fmt.Sprintf("%s", a.Clamp(11, 3)) != e {
+		t.Fatalf("clamp failed: got %s, expected %s", a.Clamp(11, 3), e)
+	}
+}
+
+func TestMapFunc(t *testing.T) {
+	a := New(Range(1, 3), Range(5, 6), PosInf(10))
+	b, err := a.Map(func(x int) int { return 2 * x })
+	if err != nil {
+		t.Fatalf("map failed: %v", err)
+	}
+	e := "{2:6, 10:12, 20:∞}"
+	if fmt.Sprintf("%s", b) != e {
+		t.Fatalf("map failed: got %s, expected %s", b, e)
+	}
+
+	b, err = a.Map(func(x int) int { return -x })
+	if err != nil {
+		t.Fatalf("map failed: %v", err)
+	}
+	e = "{-∞:-10, -6:-5, -3:-1}"
+	if fmt.Sprintf("%s", b) != e {
+		t.Fatalf("map failed: got %s, expected %s", b, e)
+	}
+
+	if _, err := New(Range(-5, 5)).Map(func(x int) int { return x * x }); err == nil {
+		t.Fatalf("map failed: expected error for function which is not monotone")
+	}
+}
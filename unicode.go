@@ -0,0 +1,150 @@
+package intset
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FromRangeTable returns a new set holding the runes of the table t.
+func FromRangeTable(t *unicode.RangeTable) *IntSet {
+	b := &Builder{}
+	for _, r := range t.R16 {
+		addStrided(b, int(r.Lo), int(r.Hi), int(r.Stride))
+	}
+	for _, r := range t.R32 {
+		addStrided(b, int(r.Lo), int(r.Hi), int(r.Stride))
+	}
+
+	return b.Build()
+}
+
+// addStrided adds every stride integer from lo to hi to the builder.
+func addStrided(b *Builder, lo, hi, stride int) {
+	if stride <= 1 {
+		b.AddRange(lo, hi)
+		return
+	}
+	for i := lo; ; i += stride {
+		b.Add(i)
+		// hi-i can not overflow, unlike i+stride
+		if hi-i < stride {
+			break
+		}
+	}
+}
+
+// ToRangeTable returns the set as a range table, usable with the
+// functions of the unicode package. Runs of single runes with a
+// common distance are compacted into entries with a Stride larger
+// than 1. An error is returned if the set is infinite, or if it holds
+// integers which are not valid runes.
+func (a *IntSet) ToRangeTable() (*unicode.RangeTable, error) {
+	a, err := a.contiguous()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &unicode.RangeTable{}
+	if len(a.elements) == 0 {
+		return t, nil
+	}
+
+	first, last := a.elements[0], a.elements[len(a.elements)-1]
+	if first.first < 0 || last.last > unicode.MaxRune {
+		return nil, fmt.Errorf("intset: %s holds integers outside the rune range U+0000%cU+%04X", a, 0x2013, unicode.MaxRune)
+	}
+
+	// singles holds a run of single runes not yet emitted
+	var singles []int
+	emit := func(lo, hi, stride int) {
+		if hi <= 0xffff {
+			t.R16 = append(t.R16, unicode.Range16{Lo: uint16(lo), Hi: uint16(hi), Stride: uint16(stride)})
+			if hi <= unicode.MaxLatin1 {
+				t.LatinOffset++
+			}
+		} else {
+			t.R32 = append(t.R32, unicode.Range32{Lo: uint32(lo), Hi: uint32(hi), Stride: uint32(stride)})
+		}
+	}
+	flush := func() {
+		for i := 0; i < len(singles); {
+			j := i + 1
+			if j < len(singles) {
+				d := singles[j] - singles[i]
+				for j+1 < len(singles) && singles[j+1]-singles[j] == d {
+					j++
+				}
+				emit(singles[i], singles[j], d)
+				i = j + 1
+				continue
+			}
+			emit(singles[i], singles[i], 1)
+			i = j
+		}
+		singles = singles[:0]
+	}
+
+	for _, e := range a.elements {
+		lo, hi := e.first, e.last
+		if lo <= 0xffff && hi > 0xffff {
+			// split ranges crossing the R16/R32 boundary
+			flush()
+			emit(lo, 0xffff, 1)
+			lo = 0x10000
+		}
+		if lo == hi {
+			if len(singles) > 0 && singles[0] <= 0xffff && lo > 0xffff {
+				flush()
+			}
+			singles = append(singles, lo)
+			continue
+		}
+		flush()
+		emit(lo, hi, 1)
+	}
+	flush()
+
+	return t, nil
+}
+
+// RuneString returns the set in a human readable form like String,
+// but with the integers formatted as Unicode code points, e.g.
+// {U+0041–U+005A, U+0061}.
+func (a *IntSet) RuneString() string {
+	if c, err := a.contiguous(); err == nil {
+		a = c
+	}
+	if len(a.elements) == 0 {
+		return fmt.Sprintf("{%c}", 0x2205)
+	}
+
+	var ents []string
+	for _, e := range a.elements {
+		var s string
+		if e.stride > 1 {
+			s = e.String()
+		} else if e.all {
+			s = fmt.Sprintf("-%c%c%c", 0x221e, 0x2013, 0x221e)
+		} else if e.neginf {
+			s = fmt.Sprintf("-%c%c%s", 0x221e, 0x2013, codePoint(e.first))
+		} else if e.posinf {
+			s = fmt.Sprintf("%s%c%c", codePoint(e.first), 0x2013, 0x221e)
+		} else if e.first == e.last {
+			s = codePoint(e.first)
+		} else {
+			s = fmt.Sprintf("%s%c%s", codePoint(e.first), 0x2013, codePoint(e.last))
+		}
+		ents = append(ents, s)
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(ents, ", "))
+}
+
+// codePoint returns n formatted as a Unicode code point.
+func codePoint(n int) string {
+	if n < 0 {
+		return fmt.Sprintf("-U+%04X", -n)
+	}
+	return fmt.Sprintf("U+%04X", n)
+}
@@ -0,0 +1,99 @@
+package intset
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+)
+
+func TestFromRangeTable(t *testing.T) {
+	rt := &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 0x41, Hi: 0x5a, Stride: 1},
+			{Lo: 0x100, Hi: 0x106, Stride: 2},
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x10400, Hi: 0x10401, Stride: 1},
+		},
+	}
+	a := FromRangeTable(rt)
+	e := "{65:90, 256, 258, 260, 262, 66560:66561}"
+	if fmt.Sprintf("%s", a) != e {
+		t.Fatalf("from range table failed: got %s, expected %s", a, e)
+	}
+}
+
+func TestFromRangeTableLimit(t *testing.T) {
+	b := &Builder{}
+	addStrided(b, intMax-8, intMax, 4)
+	a := b.Build()
+	e := New(Int(intMax-8), Int(intMax-4), Int(intMax))
+	if !a.Equal(e) {
+		t.Fatalf("add strided failed: got %s, expected %s", a, e)
+	}
+
+	b = &Builder{}
+	addStrided(b, intMax-9, intMax, 4)
+	a = b.Build()
+	e = New(Int(intMax-9), Int(intMax-5), Int(intMax-1))
+	if !a.Equal(e) {
+		t.Fatalf("add strided failed: got %s, expected %s", a, e)
+	}
+
+	rt := &unicode.RangeTable{R32: []unicode.Range32{{Lo: 0xfffffff0, Hi: 0xffffffff, Stride: 5}}}
+	a = FromRangeTable(rt)
+	if c, _ := a.Cardinality(); c != 4 {
+		t.Fatalf("from range table failed: got %s, expected 4 runes", a)
+	}
+}
+
+func TestToRangeTable(t *testing.T) {
+	a := New(Range(0x41, 0x5a), Int(0x100), Int(0x102), Int(0x104), Int(0x200), Range(0xfff0, 0x10010), Int(0x10100), Int(0x10200))
+	rt, err := a.ToRangeTable()
+	if err != nil {
+		t.Fatalf("to range table failed: %v", err)
+	}
+
+	e16 := []unicode.Range16{
+		{Lo: 0x41, Hi: 0x5a, Stride: 1},
+		{Lo: 0x100, Hi: 0x104, Stride: 2},
+		{Lo: 0x200, Hi: 0x200, Stride: 1},
+		{Lo: 0xfff0, Hi: 0xffff, Stride: 1},
+	}
+	e32 := []unicode.Range32{
+		{Lo: 0x10000, Hi: 0x10010, Stride: 1},
+		{Lo: 0x10100, Hi: 0x10200, Stride: 0x100},
+	}
+	if fmt.Sprint(rt.R16) != fmt.Sprint(e16) || fmt.Sprint(rt.R32) != fmt.Sprint(e32) || rt.LatinOffset != 1 {
+		t.Fatalf("to range table failed: got %v %v %d, expected %v %v 1", rt.R16, rt.R32, rt.LatinOffset, e16, e32)
+	}
+
+	if _, err := New(PosInf(0)).ToRangeTable(); err != ErrInfinite {
+		t.Fatalf("to range table failed: got %v, expected %v", err, ErrInfinite)
+	}
+	if _, err := New(Int(-1)).ToRangeTable(); err == nil {
+		t.Fatalf("to range table failed: expected error for negative runes")
+	}
+}
+
+func TestRangeTableRoundTrip(t *testing.T) {
+	a := FromRangeTable(unicode.Letter).Difference(FromRangeTable(unicode.Latin))
+	rt, err := a.ToRangeTable()
+	if err != nil {
+		t.Fatalf("to range table failed: %v", err)
+	}
+	for r := rune(0); r <= 0x20000; r++ {
+		e := unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r)
+		if unicode.Is(rt, r) != e {
+			t.Fatalf("range table round trip failed for %U: got %t, expected %t", r, !e, e)
+		}
+	}
+}
+
+func TestRuneString(t *testing.T) {
+	a := New(Range('A', 'Z'), Int('_'), PosInf(0x10000))
+	e := "{U+0041–U+005A, U+005F, U+10000–∞}"
+	if a.RuneString() != e {
+		t.Fatalf("rune string failed: got %s, expected %s", a.RuneString(), e)
+	}
+}